@@ -1,5 +1,11 @@
 package base
 
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
 // Rank is the rank of a card, for human usage
 type Rank int
 
@@ -72,49 +78,97 @@ func (c Card) GetSuit() Suit {
 func (c Card) String() string {
 	rank := c.GetRank()
 	suit := c.GetSuit()
-
-	var r, s string
-	switch rank {
-	case Two:
-		r = "2"
-	case Three:
-		r = "3"
-	case Four:
-		r = "4"
-	case Five:
-		r = "5"
-	case Six:
-		r = "6"
-	case Seven:
-		r = "7"
-	case Eight:
-		r = "8"
-	case Nine:
-		r = "9"
-	case Ten:
-		r = "T"
-	case Jack:
-		r = "J"
-	case Queen:
-		r = "Q"
-	case King:
-		r = "K"
-	case Ace:
-		r = "A"
-	default:
+	if rank < Two || rank > Ace || suit < Club || suit > Spade {
 		return "(invalid)"
 	}
-	switch suit {
-	case Club:
-		s = "C"
-	case Diamond:
-		s = "D"
-	case Heart:
-		s = "H"
-	case Spade:
-		s = "S"
-	default:
+	return rank.String() + string(suitNotation[suit-Club])
+}
+
+const rankNotation = "23456789TJQKA"
+const suitNotation = "cdhs"
+
+// String returns the rank written as a single character, e.g. "A" or "T"
+func (r Rank) String() string {
+	if r < Two || r > Ace {
 		return "(invalid)"
 	}
-	return "(" + r + "," + s + ")"
+	return string(rankNotation[r-Two])
+}
+
+// ParseRank parses a rank written as a single character in either case, e.g. "A", "a", or "T"
+func ParseRank(r rune) (Rank, error) {
+	i := strings.IndexRune(rankNotation, unicode.ToUpper(r))
+	if i < 0 {
+		return 0, fmt.Errorf("invalid rank %q", r)
+	}
+	return Two + Rank(i), nil
+}
+
+// ParseCard parses a card written as a rank followed by a suit, e.g. "As", "AS", "a♠", "10h", or "Td"
+func ParseCard(s string) (Card, error) {
+	cards, err := ParseCards(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(cards) != 1 {
+		return 0, fmt.Errorf("expected a single card, got %q", s)
+	}
+	return cards[0], nil
+}
+
+// ParseCards parses a list of distinct cards written as in ParseCard, optionally separated by spaces or commas, e.g.
+// "AsKd", "As Kd", or "A♠, K♦"
+func ParseCards(s string) ([]Card, error) {
+	result := []Card{}
+	seen := make(map[Card]bool)
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) || runes[i] == ',' {
+			i++
+			continue
+		}
+
+		start := i
+		var rank Rank
+		if runes[i] == '1' && i+1 < len(runes) && runes[i+1] == '0' {
+			rank = Ten
+			i += 2
+		} else if r, err := ParseRank(runes[i]); err == nil {
+			rank = r
+			i++
+		} else {
+			return nil, fmt.Errorf("%v in %q", err, s)
+		}
+
+		if i >= len(runes) {
+			return nil, fmt.Errorf("missing suit for %q in %q", string(runes[start:]), s)
+		}
+		suit, ok := parseSuit(runes[i])
+		if !ok {
+			return nil, fmt.Errorf("invalid suit %q in %q", runes[i], s)
+		}
+		i++
+
+		c := NewCard(rank, suit)
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate card %v in %q", c, s)
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+func parseSuit(r rune) (Suit, bool) {
+	switch r {
+	case 'c', 'C', '♣', '♧':
+		return Club, true
+	case 'd', 'D', '♦', '♢':
+		return Diamond, true
+	case 'h', 'H', '♥', '♡':
+		return Heart, true
+	case 's', 'S', '♠', '♤':
+		return Spade, true
+	}
+	return 0, false
 }
@@ -1,7 +1,9 @@
 package base_test
 
 import (
+	"reflect"
 	"testing"
+	"unicode"
 
 	"github.com/shishichen/strategic-parrot/base"
 )
@@ -33,10 +35,10 @@ func TestString(t *testing.T) {
 		card base.Card
 		want string
 	}{
-		{"(4,C)", base.NewCard(base.Four, base.Club), "(4,C)"},
-		{"(7,D)", base.NewCard(base.Seven, base.Diamond), "(7,D)"},
-		{"(2,H)", base.NewCard(base.Two, base.Heart), "(2,H)"},
-		{"(Q,S)", base.NewCard(base.Queen, base.Spade), "(Q,S)"},
+		{"4c", base.NewCard(base.Four, base.Club), "4c"},
+		{"7d", base.NewCard(base.Seven, base.Diamond), "7d"},
+		{"2h", base.NewCard(base.Two, base.Heart), "2h"},
+		{"Qs", base.NewCard(base.Queen, base.Spade), "Qs"},
 		{"invalid", base.Card(0xff), "(invalid)"},
 	}
 	for _, tt := range tests {
@@ -47,3 +49,85 @@ func TestString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRank(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       rune
+		want    base.Rank
+		success bool
+	}{
+		{"two", '2', base.Two, true},
+		{"ten", 'T', base.Ten, true},
+		{"lower case", 'q', base.Queen, true},
+		{"ace", 'A', base.Ace, true},
+		{"invalid", 'X', 0, false},
+		{"one", '1', 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := base.ParseRank(tt.r)
+			if (err == nil) != tt.success || got != tt.want {
+				t.Errorf("ParseRank() = %v, %v, want %v, success %v", got, err, tt.want, tt.success)
+			}
+			if tt.success && got.String() != string(unicode.ToUpper(tt.r)) {
+				t.Errorf("String() = %v, want %c", got.String(), unicode.ToUpper(tt.r))
+			}
+		})
+	}
+}
+
+func TestParseCards(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []base.Card
+		success bool
+	}{
+		{"empty", "", []base.Card{}, true},
+		{"compact", "As", []base.Card{base.NewCard(base.Ace, base.Spade)}, true},
+		{"upper case", "AS", []base.Card{base.NewCard(base.Ace, base.Spade)}, true},
+		{"symbol", "a♠", []base.Card{base.NewCard(base.Ace, base.Spade)}, true},
+		{"ten", "10h", []base.Card{base.NewCard(base.Ten, base.Heart)}, true},
+		{"several", "Td9c", []base.Card{base.NewCard(base.Ten, base.Diamond), base.NewCard(base.Nine, base.Club)}, true},
+		{"separated", "Kd, 2h 7♣", []base.Card{base.NewCard(base.King, base.Diamond), base.NewCard(base.Two, base.Heart),
+			base.NewCard(base.Seven, base.Club)}, true},
+		{"invalid rank", "1s", nil, false},
+		{"invalid suit", "Ax", nil, false},
+		{"missing suit", "AsK", nil, false},
+		{"duplicate", "As AS", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := base.ParseCards(tt.s)
+			if (err == nil) != tt.success {
+				t.Errorf("ParseCards() error = %v, want success %v", err, tt.success)
+			}
+			if tt.success && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCards() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    base.Card
+		success bool
+	}{
+		{"card", "Qs", base.NewCard(base.Queen, base.Spade), true},
+		{"round trip", base.NewCard(base.Seven, base.Diamond).String(), base.NewCard(base.Seven, base.Diamond), true},
+		{"empty", "", 0, false},
+		{"several", "QsQd", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := base.ParseCard(tt.s)
+			if (err == nil) != tt.success || got != tt.want {
+				t.Errorf("ParseCard() = %v, %v, want %v, success %v", got, err, tt.want, tt.success)
+			}
+		})
+	}
+}
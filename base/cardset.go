@@ -0,0 +1,140 @@
+package base
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// CardSet is an unordered set of cards, with one bit per card
+// Each suit occupies 13 consecutive bits, one per rank from lowest to highest, starting with clubs in the lowest bits
+type CardSet uint64
+
+// NewCardSet returns a set of the given cards
+func NewCardSet(cards ...Card) CardSet {
+	s := CardSet(0)
+	for _, c := range cards {
+		s = s.Add(c)
+	}
+	return s
+}
+
+// NewCardSetFromKey returns a set of the cards in the key
+func NewCardSetFromKey(key Key) CardSet {
+	return NewCardSet(ParseKey(key)...)
+}
+
+func cardBit(c Card) CardSet {
+	return 1 << (uint(c.GetSuit()-Club)*13 + uint(c.GetRank()-Two))
+}
+
+// Add returns the set with the card added
+func (s CardSet) Add(c Card) CardSet {
+	return s | cardBit(c)
+}
+
+// Remove returns the set with the card removed
+func (s CardSet) Remove(c Card) CardSet {
+	return s &^ cardBit(c)
+}
+
+// Contains returns whether the card is in the set
+func (s CardSet) Contains(c Card) bool {
+	return s&cardBit(c) != 0
+}
+
+// Union returns the cards in either set
+func (s CardSet) Union(other CardSet) CardSet {
+	return s | other
+}
+
+// Intersection returns the cards in both sets
+func (s CardSet) Intersection(other CardSet) CardSet {
+	return s & other
+}
+
+// Difference returns the cards in this set but not the other
+func (s CardSet) Difference(other CardSet) CardSet {
+	return s &^ other
+}
+
+// Overlaps returns whether the sets share any card
+func (s CardSet) Overlaps(other CardSet) bool {
+	return s&other != 0
+}
+
+// Count returns the number of cards in the set
+func (s CardSet) Count() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// ForEach calls the function for each card in the set, from the lowest suit to the highest and the lowest rank to the
+// highest within each suit, without allocating
+func (s CardSet) ForEach(f func(Card)) {
+	for s != 0 {
+		i := bits.TrailingZeros64(uint64(s))
+		f(NewCard(Two+Rank(i%13), Club+Suit(i/13)))
+		s &= s - 1
+	}
+}
+
+// Cards returns the cards in the set, in the same order as ForEach
+func (s CardSet) Cards() []Card {
+	result := make([]Card, 0, s.Count())
+	s.ForEach(func(c Card) { result = append(result, c) })
+	return result
+}
+
+// Key returns the key of the set, which can only be generated for up to 8 cards
+func (s CardSet) Key() (Key, error) {
+	if s.Count() > 8 {
+		return 0, fmt.Errorf("key can only be generated for up to 8 cards")
+	}
+	return GetKey(s.Cards())
+}
+
+func (s CardSet) String() string {
+	return fmt.Sprint(s.Cards())
+}
+
+// suitMask returns the 13 bit mask of ranks in the given suit
+func (s CardSet) suitMask(suit Suit) uint16 {
+	return uint16(s>>(uint(suit-Club)*13)) & 0x1fff
+}
+
+// GetCardSetScore returns the same score as GetScore for a set of 5, 6, or 7 cards, without allocating
+func GetCardSetScore(s CardSet) (Score, error) {
+	if count := s.Count(); count < 5 || count > 7 {
+		return 0, fmt.Errorf("only a set of 5, 6, or 7 cards can be scored")
+	}
+
+	c, d, h, sp := s.suitMask(Club), s.suitMask(Diamond), s.suitMask(Heart), s.suitMask(Spade)
+	ones := c | d | h | sp
+	twos := (c & d) | (c & h) | (c & sp) | (d & h) | (d & sp) | (h & sp)
+	threes := (c & d & h) | (c & d & sp) | (c & h & sp) | (d & h & sp)
+	fours := c & d & h & sp
+	return scoreMasks([4]uint16{c, d, h, sp}, ones, twos, threes, fours), nil
+}
+
+// GetCardSetCombinations returns all combinations of size k from the given set
+func GetCardSetCombinations(s CardSet, k int) []CardSet {
+	n := s.Count()
+	if k < 0 || k > n {
+		return []CardSet{}
+	}
+	result := make([]CardSet, 0, numCombinations(n, k))
+	return generateCardSetCombinations(0, s, k, result)
+}
+
+// generateCardSetCombinations generates combinations with the fixed prefix, choosing k from the remaining
+// Requires: 0 <= k <= number of remaining cards
+func generateCardSetCombinations(prefix, remaining CardSet, k int, result []CardSet) []CardSet {
+	if k == 0 {
+		return append(result, prefix)
+	}
+	for remaining.Count() >= k {
+		lowest := remaining & -remaining
+		remaining &^= lowest
+		result = generateCardSetCombinations(prefix|lowest, remaining, k-1, result)
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package base_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestCardSet(t *testing.T) {
+	ace, king, queen := base.NewCard(base.Ace, base.Spade), base.NewCard(base.King, base.Heart),
+		base.NewCard(base.Queen, base.Club)
+	x := base.NewCardSet(ace, king)
+	y := base.NewCardSet(king, queen)
+
+	if !x.Contains(ace) || !x.Contains(king) || x.Contains(queen) {
+		t.Errorf("Contains() is wrong for %v", x)
+	}
+	if got := x.Union(y); got != base.NewCardSet(ace, king, queen) {
+		t.Errorf("Union() = %v, want %v", got, []base.Card{queen, king, ace})
+	}
+	if got := x.Intersection(y); got != base.NewCardSet(king) {
+		t.Errorf("Intersection() = %v, want %v", got, []base.Card{king})
+	}
+	if got := x.Difference(y); got != base.NewCardSet(ace) {
+		t.Errorf("Difference() = %v, want %v", got, []base.Card{ace})
+	}
+	if !x.Overlaps(y) || x.Overlaps(base.NewCardSet(queen)) {
+		t.Errorf("Overlaps() is wrong for %v", x)
+	}
+	if got := x.Remove(ace); got != base.NewCardSet(king) {
+		t.Errorf("Remove() = %v, want %v", got, []base.Card{king})
+	}
+	if got := base.NewCardSet(base.NewDeck().GetCards()...).Count(); got != 52 {
+		t.Errorf("Count() = %v, want 52", got)
+	}
+}
+
+func TestCardSetCards(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []base.Card
+	}{
+		{"empty", []base.Card{}},
+		{"set of 2", []base.Card{base.NewCard(base.Ace, base.Heart), base.NewCard(base.King, base.Heart)}},
+		{"set of 5", []base.Card{base.NewCard(base.Two, base.Club), base.NewCard(base.Jack, base.Diamond),
+			base.NewCard(base.Queen, base.Heart), base.NewCard(base.King, base.Spade),
+			base.NewCard(base.Ace, base.Spade)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := base.NewCardSet(tt.cards...)
+			if got := s.Cards(); !equivalent(got, tt.cards) {
+				t.Errorf("Cards() = %v, want %v", got, tt.cards)
+			}
+			want, _ := base.GetKey(tt.cards)
+			if key, err := s.Key(); err != nil || key != want {
+				t.Errorf("Key() = %x, want %x", key, want)
+			}
+			if got := base.NewCardSetFromKey(want); got != s {
+				t.Errorf("NewCardSetFromKey() = %v, want %v", got, s)
+			}
+		})
+	}
+	if _, err := base.NewCardSet(base.NewDeck().GetCards()[:9]...).Key(); err == nil {
+		t.Errorf("Key() of 9 cards succeeded, want error")
+	}
+}
+
+func TestCardSetScore(t *testing.T) {
+	for _, c := range base.GetCombinations(base.NewDeck().GetCards(), 5) {
+		want, _ := base.GetFastScore(c)
+		if got, err := base.GetCardSetScore(base.NewCardSet(c...)); err != nil || got != want {
+			t.Fatalf("GetCardSetScore(%v) = %x, want %x", c, got, want)
+		}
+	}
+	hand := []base.Card{base.NewCard(base.Seven, base.Diamond), base.NewCard(base.Seven, base.Heart),
+		base.NewCard(base.Seven, base.Spade), base.NewCard(base.Seven, base.Club), base.NewCard(base.Six, base.Spade),
+		base.NewCard(base.Six, base.Club), base.NewCard(base.Six, base.Diamond)}
+	want, _ := base.GetScore(hand)
+	if got, err := base.GetCardSetScore(base.NewCardSet(hand...)); err != nil || got != want {
+		t.Errorf("GetCardSetScore(%v) = %x, want %x", hand, got, want)
+	}
+	if _, err := base.GetCardSetScore(base.NewCardSet(hand[:4]...)); err == nil {
+		t.Errorf("GetCardSetScore() of 4 cards succeeded, want error")
+	}
+}
+
+func TestCardSetCombinations(t *testing.T) {
+	cards := []base.Card{base.NewCard(base.Ace, base.Heart), base.NewCard(base.King, base.Heart),
+		base.NewCard(base.Queen, base.Heart), base.NewCard(base.Jack, base.Heart), base.NewCard(base.Ten, base.Heart)}
+	for k := 0; k <= len(cards)+1; k++ {
+		want := []base.CardSet{}
+		for _, c := range base.GetCombinations(cards, k) {
+			want = append(want, base.NewCardSet(c...))
+		}
+		got := base.GetCardSetCombinations(base.NewCardSet(cards...), k)
+		if len(got) != len(want) {
+			t.Fatalf("GetCardSetCombinations() returned %v combinations, want %v", len(got), len(want))
+		}
+		seen := make(map[base.CardSet]bool)
+		for _, s := range got {
+			seen[s] = true
+		}
+		for _, s := range want {
+			if !seen[s] {
+				t.Errorf("GetCardSetCombinations() is missing %v", s)
+			}
+		}
+	}
+	if got := base.GetCardSetCombinations(base.NewCardSet(cards...), 0); !reflect.DeepEqual(got, []base.CardSet{0}) {
+		t.Errorf("GetCardSetCombinations() choosing 0 = %v, want the empty set", got)
+	}
+}
+
+func BenchmarkCardSetScore(b *testing.B) {
+	s := base.NewCardSet(base.NewCard(base.Seven, base.Diamond), base.NewCard(base.Three, base.Heart),
+		base.NewCard(base.Eight, base.Diamond), base.NewCard(base.Ten, base.Spade),
+		base.NewCard(base.Six, base.Spade), base.NewCard(base.Queen, base.Club),
+		base.NewCard(base.Nine, base.Club))
+	for i := 0; i < b.N; i++ {
+		base.GetCardSetScore(s)
+	}
+}
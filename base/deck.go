@@ -22,6 +22,18 @@ func NewDeck() *Deck {
 	return deck
 }
 
+// NewShortDeck returns a new unshuffled short deck of 36 cards, as used in short-deck (6+) Hold'em, where every card
+// below a six has been removed
+func NewShortDeck() *Deck {
+	deck := &Deck{}
+	for _, rank := range []Rank{Six, Seven, Eight, Nine, Ten, Jack, Queen, King, Ace} {
+		for _, suit := range []Suit{Club, Diamond, Heart, Spade} {
+			deck.cards = append(deck.cards, NewCard(rank, suit))
+		}
+	}
+	return deck
+}
+
 // Shuffle shuffles the deck
 func (d *Deck) Shuffle() {
 	rand.Seed(time.Now().UnixNano())
@@ -45,13 +57,12 @@ func (d *Deck) Next() (Card, error) {
 
 // Remove removes a set of cards from the deck
 func (d *Deck) Remove(remove []Card) {
-	for _, r := range remove {
-		for i, c := range d.cards {
-			if c == r {
-				copy(d.cards[i:], d.cards[i+1:])
-				d.cards = d.cards[:len(d.cards)-1]
-				break
-			}
+	removed := NewCardSet(remove...)
+	remaining := d.cards[:0]
+	for _, c := range d.cards {
+		if !removed.Contains(c) {
+			remaining = append(remaining, c)
 		}
 	}
+	d.cards = remaining
 }
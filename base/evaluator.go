@@ -0,0 +1,138 @@
+package base
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// straightHigh maps a 13 bit mask of ranks to the rank of the highest card of the best straight among them, or 0 if
+// there is no straight
+var straightHigh [1 << 13]Rank
+
+// topFive maps a 13 bit mask of ranks to the (up to) 5 highest ranks among them, packed into 4 bits each from most to
+// least significant, in the same layout as the significant ranks of a score
+var topFive [1 << 13]uint64
+
+func init() {
+	for mask := 0; mask < len(straightHigh); mask++ {
+		// Aces also count as the lowest card of a straight
+		extended := mask<<1 | mask>>12
+		for high := Ace; high >= Five; high-- {
+			straight := 0x1f << (int(high) - 4)
+			if extended&straight == straight {
+				straightHigh[mask] = high
+				break
+			}
+		}
+
+		remaining := uint16(mask)
+		for i := 0; i < 5 && remaining != 0; i++ {
+			topFive[mask] |= uint64(highestRank(remaining)) << (16 - i*4)
+			remaining &^= rankBit(highestRank(remaining))
+		}
+	}
+}
+
+// GetFastScore returns the same score as GetScore, but uses lookup tables instead of sorting and does not allocate
+func GetFastScore(cards []Card) (Score, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, fmt.Errorf("only a set of 5, 6, or 7 cards can be scored")
+	}
+
+	// Track the ranks in each suit, and the ranks that appear at least once, twice, three times, and four times
+	var suits [4]uint16
+	var ones, twos, threes, fours uint16
+	for _, c := range cards {
+		bit := rankBit(c.GetRank())
+		suits[(c.GetSuit()-1)&3] |= bit
+		fours |= threes & bit
+		threes |= twos & bit
+		twos |= ones & bit
+		ones |= bit
+	}
+	return scoreMasks(suits, ones, twos, threes, fours), nil
+}
+
+// scoreMasks returns the score given the masks of ranks in each suit and the ranks appearing at least some number of
+// times. A flush rules out four of a kind and a full house, since there are at most 7 cards.
+func scoreMasks(suits [4]uint16, ones, twos, threes, fours uint16) Score {
+	for _, suit := range suits {
+		if bits.OnesCount16(suit) >= 5 {
+			if high := straightHigh[suit]; high != 0 {
+				return newScore(StraightFlush, straightRanks(high))
+			}
+			return newScore(Flush, topFive[suit])
+		}
+	}
+
+	if fours != 0 {
+		quad := highestRank(fours)
+		kicker := topFive[ones&^rankBit(quad)] >> 16
+		return newScore(FourOfAKind, repeatRank(quad, 4)<<4|kicker)
+	}
+
+	if threes != 0 {
+		trip := highestRank(threes)
+		if pairs := twos &^ rankBit(trip); pairs != 0 {
+			return newScore(FullHouse, repeatRank(trip, 3)<<8|repeatRank(highestRank(pairs), 2))
+		}
+	}
+
+	if high := straightHigh[ones]; high != 0 {
+		return newScore(Straight, straightRanks(high))
+	}
+
+	if threes != 0 {
+		trip := highestRank(threes)
+		kickers := topFive[ones&^rankBit(trip)] >> 12
+		return newScore(ThreeOfAKind, repeatRank(trip, 3)<<8|kickers)
+	}
+
+	if twos != 0 {
+		high := highestRank(twos)
+		if lows := twos &^ rankBit(high); lows != 0 {
+			low := highestRank(lows)
+			kicker := topFive[ones&^rankBit(high)&^rankBit(low)] >> 16
+			return newScore(TwoPair, repeatRank(high, 2)<<12|repeatRank(low, 2)<<4|kicker)
+		}
+		kickers := topFive[ones&^rankBit(high)] >> 8
+		return newScore(Pair, repeatRank(high, 2)<<12|kickers)
+	}
+
+	return newScore(HighCard, topFive[ones])
+}
+
+func newScore(r HandRank, significant uint64) Score {
+	return Score(uint64(r)<<20 | significant)
+}
+
+func rankBit(r Rank) uint16 {
+	return 1 << (r - 1)
+}
+
+func highestRank(mask uint16) Rank {
+	return Rank(bits.Len16(mask))
+}
+
+// repeatRank returns the rank repeated n times, packed into 4 bits each
+func repeatRank(r Rank, n int) uint64 {
+	result := uint64(0)
+	for i := 0; i < n; i++ {
+		result = result<<4 | uint64(r)
+	}
+	return result
+}
+
+// straightRanks returns the ranks of the straight with the given highest card, packed into 4 bits each, where the ace
+// is the lowest card of the lowest straight
+func straightRanks(high Rank) uint64 {
+	result := uint64(0)
+	for i := 0; i < 5; i++ {
+		r := high - Rank(i)
+		if r < Two {
+			r = Ace
+		}
+		result = result<<4 | uint64(r)
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestFastScore(t *testing.T) {
+	deck := base.NewDeck()
+	for _, c := range base.GetCombinations(deck.GetCards(), 5) {
+		want, _ := base.GetScore(c)
+		if got, err := base.GetFastScore(c); err != nil || got != want {
+			t.Fatalf("GetFastScore(%v) = %x, want %x", c, got, want)
+		}
+	}
+	if _, err := base.GetFastScore(deck.GetCards()[:4]); err == nil {
+		t.Errorf("GetFastScore() of 4 cards succeeded, want error")
+	}
+}
+
+// TestFastScoreExhaustive cross-checks every 6 and 7 card hand against GetScore, which takes several minutes
+func TestFastScoreExhaustive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping exhaustive cross-check in short mode")
+	}
+	deck := base.NewDeck()
+	for _, c := range base.GetCombinations(deck.GetCards(), 6) {
+		want, _ := base.GetScore(c)
+		if got, _ := base.GetFastScore(c); got != want {
+			t.Fatalf("GetFastScore(%v) = %x, want %x", c, got, want)
+		}
+	}
+
+	cards := deck.GetCards()
+	n := len(cards)
+	hand := make([]base.Card, 7)
+	for a := 0; a < n; a++ {
+		hand[0] = cards[a]
+		for b := a + 1; b < n; b++ {
+			hand[1] = cards[b]
+			for c := b + 1; c < n; c++ {
+				hand[2] = cards[c]
+				for d := c + 1; d < n; d++ {
+					hand[3] = cards[d]
+					for e := d + 1; e < n; e++ {
+						hand[4] = cards[e]
+						for f := e + 1; f < n; f++ {
+							hand[5] = cards[f]
+							for g := f + 1; g < n; g++ {
+								hand[6] = cards[g]
+								want, _ := base.GetScore(hand)
+								if got, _ := base.GetFastScore(hand); got != want {
+									t.Fatalf("GetFastScore(%v) = %x, want %x", hand, got, want)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkFastScore(b *testing.B) {
+	hand := []base.Card{base.NewCard(base.Seven, base.Diamond), base.NewCard(base.Three, base.Heart),
+		base.NewCard(base.Eight, base.Diamond), base.NewCard(base.Ten, base.Spade),
+		base.NewCard(base.Six, base.Spade), base.NewCard(base.Queen, base.Club),
+		base.NewCard(base.Nine, base.Club)}
+	for i := 0; i < b.N; i++ {
+		base.GetFastScore(hand)
+	}
+}
@@ -0,0 +1,100 @@
+package base
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// LowScore is the score of the best 8-or-better low hand that can be made from a set of cards, where aces are low,
+// straights and flushes are ignored, and the hand must be made of 5 distinct ranks of eight or lower
+// Higher low scores are better lows, and a low score of 0 means there is no qualifying low
+type LowScore uint32
+
+// lowQualifier is the mask of low values that can be part of a qualifying low, from ace to eight
+const lowQualifier = 0xFF
+
+// GetLowScore returns the low score of a set of 5 to 7 cards, where any 5 of the cards can be used
+func GetLowScore(cards []Card) (LowScore, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, fmt.Errorf("only a set of 5, 6, or 7 cards can be scored")
+	}
+	mask := uint16(0)
+	for _, c := range cards {
+		mask |= lowBit(c.GetRank())
+	}
+	return getLowScore(mask), nil
+}
+
+// GetOmahaLowScore returns the low score of a hand in Omaha, which must be made of exactly 2 cards from a hole of 4 or
+// 5 cards and exactly 3 cards from a board of 3 to 5 cards
+func GetOmahaLowScore(hole []Card, board []Card) (LowScore, error) {
+	if len(hole) != 4 && len(hole) != 5 {
+		return 0, fmt.Errorf("only a hole of 4 or 5 cards can be scored in omaha")
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return 0, fmt.Errorf("only a board of 3 to 5 cards can be scored in omaha")
+	}
+
+	best := LowScore(0)
+	for a := 0; a < len(hole); a++ {
+		for b := a + 1; b < len(hole); b++ {
+			holeMask := lowBit(hole[a].GetRank()) | lowBit(hole[b].GetRank())
+			if bits.OnesCount16(holeMask&lowQualifier) != 2 {
+				continue
+			}
+			for c := 0; c < len(board); c++ {
+				for d := c + 1; d < len(board); d++ {
+					for e := d + 1; e < len(board); e++ {
+						mask := holeMask | lowBit(board[c].GetRank()) | lowBit(board[d].GetRank()) |
+							lowBit(board[e].GetRank())
+						if score := getLowScore(mask); score > best {
+							best = score
+						}
+					}
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+// GetRanks returns the ranks of the low hand from highest to lowest, with aces last, or nil if there is no low
+func (s LowScore) GetRanks() []Rank {
+	if s == 0 {
+		return nil
+	}
+	result := make([]Rank, 5)
+	for i := range result {
+		value := 9 - Rank(s>>(4*(4-i))&0xF)
+		if value == 1 {
+			result[i] = Ace
+		} else {
+			result[i] = value - 1
+		}
+	}
+	return result
+}
+
+// lowBit returns the bit of a rank in a mask of low values, where bit 0 is an ace and bit n is a rank worth n+1
+func lowBit(r Rank) uint16 {
+	if r == Ace {
+		return 1
+	}
+	return 1 << r
+}
+
+// getLowScore returns the low score of a mask of low values, made from its 5 lowest values if they qualify
+// The highest value is the most significant, and each value v is stored as 9-v so that lower lows score higher
+func getLowScore(mask uint16) LowScore {
+	mask &= lowQualifier
+	if bits.OnesCount16(mask) < 5 {
+		return 0
+	}
+	score := LowScore(0)
+	for i := 0; i < 5; i++ {
+		value := bits.TrailingZeros16(mask) + 1
+		mask &= mask - 1
+		score |= LowScore(9-value) << (4 * i)
+	}
+	return score
+}
@@ -0,0 +1,89 @@
+package base_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestLowScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards string
+		want  []base.Rank // the ranks of the low, or nil if there is none
+	}{
+		{"wheel", "As2d3c4h5s", []base.Rank{base.Five, base.Four, base.Three, base.Two, base.Ace}},
+		{"flush is ignored", "Ah3h5h7h8h", []base.Rank{base.Eight, base.Seven, base.Five, base.Three, base.Ace}},
+		{"lowest five of seven", "8s7d6c2h3sAdKs", []base.Rank{base.Seven, base.Six, base.Three, base.Two, base.Ace}},
+		{"pairs are skipped", "2s2d3c4h4s6d8c", []base.Rank{base.Eight, base.Six, base.Four, base.Three, base.Two}},
+		{"nine does not qualify", "As2d3c4h9s", nil},
+		{"too few distinct ranks", "As2d3c4hAhKs2c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, _ := base.ParseCards(tt.cards)
+			got, err := base.GetLowScore(cards)
+			if err != nil {
+				t.Fatalf("GetLowScore() error = %v", err)
+			}
+			if (got == 0) != (tt.want == nil) {
+				t.Fatalf("GetLowScore() = %x, want qualifying %v", got, tt.want != nil)
+			}
+			if ranks := got.GetRanks(); !reflect.DeepEqual(ranks, tt.want) {
+				t.Errorf("GetRanks() = %v, want %v", ranks, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowScoreOrder(t *testing.T) {
+	// From best to worst
+	lows := []string{"As2d3c4h5s", "As2d3c4h6s", "As2d3c5h6s", "2s3d4c5h6s", "As2d3c4h8s", "4s5d6c7h8s"}
+	previous := base.LowScore(1 << 31)
+	for _, low := range lows {
+		cards, _ := base.ParseCards(low)
+		score, _ := base.GetLowScore(cards)
+		if score >= previous {
+			t.Errorf("GetLowScore(%v) = %x, want less than %x", low, score, previous)
+		}
+		previous = score
+	}
+}
+
+func TestOmahaLowScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		hole    string
+		board   string
+		want    string // the low five cards, or empty if there is no low
+		success bool
+	}{
+		{"must use two hole cards", "As2dKcKh", "3c4h5s8d9c", "As2d3c4h5s", true},
+		{"cannot use three hole cards", "As2d3cKh", "4h5sKdQc", "", true},
+		{"cannot use four board cards", "AsKdKcQh", "2c3h4s5d", "", true},
+		{"counterfeited", "As2dKcQh", "Ac2h3s6d8c", "As2d3s6d8c", true},
+		{"hold'em hole", "As2d", "3c4h5s", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, _ := base.ParseCards(tt.hole)
+			board, _ := base.ParseCards(tt.board)
+			got, err := base.GetOmahaLowScore(hole, board)
+			if (err == nil) != tt.success {
+				t.Fatalf("GetOmahaLowScore() error = %v, want success %v", err, tt.success)
+			}
+			if !tt.success {
+				return
+			}
+			want := base.LowScore(0)
+			if tt.want != "" {
+				five, _ := base.ParseCards(tt.want)
+				want, _ = base.GetLowScore(five)
+			}
+			if got != want {
+				t.Errorf("GetOmahaLowScore() = %x, want %x", got, want)
+			}
+		})
+	}
+}
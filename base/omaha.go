@@ -0,0 +1,37 @@
+package base
+
+import "fmt"
+
+// GetOmahaScore returns the score of the best poker hand in Omaha, which must be made of exactly 2 cards from a hole of
+// 4 or 5 cards and exactly 3 cards from a board of 3 to 5 cards
+// Scores are ordered the same as GetScore
+func GetOmahaScore(hole []Card, board []Card) (Score, error) {
+	if len(hole) != 4 && len(hole) != 5 {
+		return 0, fmt.Errorf("only a hole of 4 or 5 cards can be scored in omaha")
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return 0, fmt.Errorf("only a board of 3 to 5 cards can be scored in omaha")
+	}
+
+	best := Score(0)
+	var hand [5]Card
+	for a := 0; a < len(hole); a++ {
+		hand[0] = hole[a]
+		for b := a + 1; b < len(hole); b++ {
+			hand[1] = hole[b]
+			for c := 0; c < len(board); c++ {
+				hand[2] = board[c]
+				for d := c + 1; d < len(board); d++ {
+					hand[3] = board[d]
+					for e := d + 1; e < len(board); e++ {
+						hand[4] = board[e]
+						if score, _ := GetFastScore(hand[:]); score > best {
+							best = score
+						}
+					}
+				}
+			}
+		}
+	}
+	return best, nil
+}
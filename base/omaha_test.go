@@ -0,0 +1,42 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestOmahaScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		hole    string
+		board   string
+		want    string // the best five cards
+		success bool
+	}{
+		{"must use two hole cards for a flush", "AsKd7c2h", "QsJsTs9s3s", "AsKdQsJsTs", true},
+		{"must use three board cards for quads", "KhKcKd2h", "KsQs7h", "KhKcKsQs7h", true},
+		{"two pair from the hole", "AsAdKsKd", "2c7h9d", "AsAd9d7h2c", true},
+		{"wheel", "As2d9c9h", "3h4s5c", "5c4s3h2dAs", true},
+		{"five card hole", "AsAd7c7h2c", "7s8d8c", "7c7h7s8d8c", true},
+		{"hold'em hole", "AsKd", "QsJsTs", "", false},
+		{"short board", "AsKd7c2h", "QsJs", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, _ := base.ParseCards(tt.hole)
+			board, _ := base.ParseCards(tt.board)
+			got, err := base.GetOmahaScore(hole, board)
+			if (err == nil) != tt.success {
+				t.Fatalf("GetOmahaScore() error = %v, want success %v", err, tt.success)
+			}
+			if !tt.success {
+				return
+			}
+			five, _ := base.ParseCards(tt.want)
+			if want, _ := base.GetScore(five); got != want {
+				t.Errorf("GetOmahaScore() = %x, want %x", got, want)
+			}
+		})
+	}
+}
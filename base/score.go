@@ -26,22 +26,138 @@ func GetScore(cards []Card) (Score, error) {
 	return Score(score), nil
 }
 
-type ranking int
+// HandRank is the category of a poker hand, from worst to best
+type HandRank int
 
 const (
-	highCard      ranking = iota + 1 // significant ranks: cards from highest to lowest
-	pair                             // significant ranks: pair, remaining cards from highest to lowest
-	twoPair                          // significant ranks: highest pair, lowest pair, remaining highest card
-	threeOfAKind                     // significant ranks: three of a kind, remaining cards from highest to lowest
-	straight                         // significant ranks: straight from highest to lowest
-	flush                            // significant ranks: flush cards from highest to lowest
-	fullHouse                        // significant ranks: three of a kind, pair
-	fourOfAKind                      // significant ranks: four of a kind, highest remaining card
-	straightFlush                    // significant ranks: straight flush cards from highest to lowest
+	HighCard      HandRank = iota + 1 // significant ranks: cards from highest to lowest
+	Pair                              // significant ranks: pair, remaining cards from highest to lowest
+	TwoPair                           // significant ranks: highest pair, lowest pair, remaining highest card
+	ThreeOfAKind                      // significant ranks: three of a kind, remaining cards from highest to lowest
+	Straight                          // significant ranks: straight from highest to lowest
+	Flush                             // significant ranks: flush cards from highest to lowest
+	FullHouse                         // significant ranks: three of a kind, pair
+	FourOfAKind                       // significant ranks: four of a kind, highest remaining card
+	StraightFlush                     // significant ranks: straight flush cards from highest to lowest
 )
 
+func (r HandRank) String() string {
+	switch r {
+	case HighCard:
+		return "High card"
+	case Pair:
+		return "Pair"
+	case TwoPair:
+		return "Two pair"
+	case ThreeOfAKind:
+		return "Three of a kind"
+	case Straight:
+		return "Straight"
+	case Flush:
+		return "Flush"
+	case FullHouse:
+		return "Full house"
+	case FourOfAKind:
+		return "Four of a kind"
+	case StraightFlush:
+		return "Straight flush"
+	}
+	return "(invalid)"
+}
+
+// GetHandRank returns the category of the hand with the score
+func (s Score) GetHandRank() HandRank {
+	return HandRank((s >> 20) & 0xf)
+}
+
+// GetSignificantRanks returns the ranks of the 5 significant cards of the hand with the score, in order from most to
+// least significant
+func (s Score) GetSignificantRanks() []Rank {
+	result := make([]Rank, 5)
+	for i := 0; i < 5; i++ {
+		result[i] = Rank((s >> (16 - i*4)) & 0xf)
+	}
+	return result
+}
+
+// GetDescription returns a human readable description of the hand with the score, e.g. "Full house, Kings full of
+// Twos"
+func (s Score) GetDescription() string {
+	r := s.GetSignificantRanks()
+	switch s.GetHandRank() {
+	case HighCard:
+		return fmt.Sprintf("High card, %v", rankName(r[0]))
+	case Pair:
+		return fmt.Sprintf("Pair, %v", rankPlural(r[0]))
+	case TwoPair:
+		return fmt.Sprintf("Two pair, %v and %v", rankPlural(r[0]), rankPlural(r[2]))
+	case ThreeOfAKind:
+		return fmt.Sprintf("Three of a kind, %v", rankPlural(r[0]))
+	case Straight:
+		return fmt.Sprintf("Straight, %v high", rankName(r[0]))
+	case Flush:
+		return fmt.Sprintf("Flush, %v high", rankName(r[0]))
+	case FullHouse:
+		return fmt.Sprintf("Full house, %v full of %v", rankPlural(r[0]), rankPlural(r[3]))
+	case FourOfAKind:
+		return fmt.Sprintf("Four of a kind, %v", rankPlural(r[0]))
+	case StraightFlush:
+		if r[0] == Ace {
+			return "Royal flush"
+		}
+		return fmt.Sprintf("Straight flush, %v high", rankName(r[0]))
+	}
+	return "(invalid)"
+}
+
+var rankNames = []string{"Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen",
+	"King", "Ace"}
+
+func rankName(r Rank) string {
+	if r < Two || r > Ace {
+		return "(invalid)"
+	}
+	return rankNames[r-Two]
+}
+
+func rankPlural(r Rank) string {
+	if r == Six {
+		return "Sixes"
+	}
+	return rankName(r) + "s"
+}
+
+// GetBestFive returns the 5 cards that make the best poker hand out of 5, 6, or 7 cards, in order from most to least
+// significant
+func GetBestFive(cards []Card) ([]Card, error) {
+	best, err := GetFastScore(cards)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, five := range GetCombinations(cards, 5) {
+		if score, _ := GetFastScore(five); score != best {
+			continue
+		}
+		// Order the cards by their significance, taking each significant rank from the remaining cards
+		result := make([]Card, 0, 5)
+		used := CardSet(0)
+		for _, r := range best.GetSignificantRanks() {
+			for _, c := range five {
+				if c.GetRank() == r && !used.Contains(c) {
+					result = append(result, c)
+					used = used.Add(c)
+					break
+				}
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("no 5 cards make the best hand")
+}
+
 // Returns the best ranking and the ranks of the 5 significant cards, in order from most to least
-func getRanking(cards []Card) (ranking, []Rank) {
+func getRanking(cards []Card) (HandRank, []Rank) {
 	significant := make([]Rank, 5)
 
 	sorted := make([]Card, len(cards))
@@ -56,7 +172,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 			for i := 0; i < 5; i++ {
 				significant[i] = straightFlushCards[i].GetRank()
 			}
-			return straightFlush, significant
+			return StraightFlush, significant
 		}
 	}
 
@@ -67,7 +183,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 			significant[i] = mRank
 		}
 		significant[4] = nRank
-		return fourOfAKind, significant
+		return FourOfAKind, significant
 	}
 
 	// Next, full house
@@ -78,7 +194,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 		for i := 3; i < 5; i++ {
 			significant[i] = nRank
 		}
-		return fullHouse, significant
+		return FullHouse, significant
 	}
 
 	// Next, flush
@@ -86,7 +202,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 		for i := 0; i < 5; i++ {
 			significant[i] = flushCards[i].GetRank()
 		}
-		return flush, significant
+		return Flush, significant
 	}
 
 	// Next, straight
@@ -95,7 +211,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 		for i := 0; i < 5; i++ {
 			significant[i] = straightCards[i].GetRank()
 		}
-		return straight, significant
+		return Straight, significant
 	}
 
 	// Next, three of a kind
@@ -111,7 +227,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 				break
 			}
 		}
-		return threeOfAKind, significant
+		return ThreeOfAKind, significant
 	}
 
 	// Next, two pair
@@ -129,7 +245,7 @@ func getRanking(cards []Card) (ranking, []Rank) {
 				break
 			}
 		}
-		return twoPair, significant
+		return TwoPair, significant
 	}
 
 	// Next, pair
@@ -149,14 +265,14 @@ func getRanking(cards []Card) (ranking, []Rank) {
 				break
 			}
 		}
-		return pair, significant
+		return Pair, significant
 	}
 
 	// Finally, if we have nothing else, we just have a high card
 	for i := 0; i < 5; i++ {
 		significant[i] = sorted[i].GetRank()
 	}
-	return highCard, significant
+	return HighCard, significant
 }
 
 func hasSomeOfAKind(cards []Card) (m int, n int, mRank Rank, nRank Rank) {
@@ -188,3 +188,44 @@ func BenchmarkScore(b *testing.B) {
 			base.NewCard(base.Nine, base.Club)})
 	}
 }
+
+func TestDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    string
+		rank     base.HandRank
+		want     string
+		bestFive string
+	}{
+		{"royal flush", "AdKdQdJdTd9d2c", base.StraightFlush, "Royal flush", "AdKdQdJdTd"},
+		{"straight flush", "5h4h3h2hAh", base.StraightFlush, "Straight flush, Five high", "5h4h3h2hAh"},
+		{"four of a kind", "AsAcAhAdKcQhQd", base.FourOfAKind, "Four of a kind, Aces", "AsAcAhAdKc"},
+		{"full house", "2s2cKhKcKd2hQd", base.FullHouse, "Full house, Kings full of Twos", "KhKcKd2s2c"},
+		{"flush", "7s8sQs2s5sKs6s", base.Flush, "Flush, King high", "KsQs8s7s6s"},
+		{"straight", "Ts6c7d9h8h7cTd", base.Straight, "Straight, Ten high", "Ts9h8h7d6c"},
+		{"three of a kind", "2cJs9c9h4d9d", base.ThreeOfAKind, "Three of a kind, Nines", "9c9h9dJs4d"},
+		{"two pair", "Qs4c8h7c4dQh8d", base.TwoPair, "Two pair, Queens and Eights", "QsQh8h8d7c"},
+		{"pair", "2h7s8cQd8hKc6d", base.Pair, "Pair, Eights", "8c8hKcQd7s"},
+		{"pair of sixes", "6h6s8cQd2h", base.Pair, "Pair, Sixes", "6h6sQd8c2h"},
+		{"high card", "7s8cQd2h5hKc6d", base.HighCard, "High card, King", "KcQd8c7s6d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := base.ParseCards(tt.cards)
+			if err != nil {
+				t.Fatalf("ParseCards() error = %v", err)
+			}
+			score, _ := base.GetScore(cards)
+			if got := score.GetHandRank(); got != tt.rank {
+				t.Errorf("GetHandRank() = %v, want %v", got, tt.rank)
+			}
+			if got := score.GetDescription(); got != tt.want {
+				t.Errorf("GetDescription() = %v, want %v", got, tt.want)
+			}
+			want, _ := base.ParseCards(tt.bestFive)
+			if got, err := base.GetBestFive(cards); err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("GetBestFive() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package base
+
+import "fmt"
+
+// shortDeckWheel is the mask of ranks in the lowest straight of a short deck, from an ace to a nine
+const shortDeckWheel = 1<<(Ace-1) | 1<<(Six-1) | 1<<(Seven-1) | 1<<(Eight-1) | 1<<(Nine-1)
+
+// GetShortDeckScore returns the score of the best poker hand that can be made from a set of 5 to 7 cards in
+// short-deck (6+) Hold'em, where A-6-7-8-9 is the lowest straight and a flush beats a full house
+// The hand rank and significant ranks of the score are the same as those of GetScore, but the ordering of full houses
+// and flushes is kept above them, so short-deck scores should only be compared with each other
+func GetShortDeckScore(cards []Card) (Score, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, fmt.Errorf("only a set of 5, 6, or 7 cards can be scored")
+	}
+
+	var suits [4]uint16
+	var ones, twos, threes, fours uint16
+	for _, c := range cards {
+		if c.GetRank() < Six {
+			return 0, fmt.Errorf("card %v is not in a short deck", c)
+		}
+		bit := rankBit(c.GetRank())
+		suits[(c.GetSuit()-1)&3] |= bit
+		fours |= threes & bit
+		threes |= twos & bit
+		twos |= ones & bit
+		ones |= bit
+	}
+	score := scoreMasks(suits, ones, twos, threes, fours)
+
+	// A-6-7-8-9 is only a straight in a short deck, and it is the lowest one, so it only matters without a better hand
+	// of the same kind
+	if score.GetHandRank() < StraightFlush {
+		for _, suit := range suits {
+			if suit&shortDeckWheel == shortDeckWheel {
+				score = newScore(StraightFlush, shortDeckWheelRanks())
+			}
+		}
+	}
+	if score.GetHandRank() < Straight && ones&shortDeckWheel == shortDeckWheel {
+		score = newScore(Straight, shortDeckWheelRanks())
+	}
+
+	// Fewer cards make flushes rarer than full houses, so they swap places
+	order := score.GetHandRank()
+	switch order {
+	case Flush:
+		order = FullHouse
+	case FullHouse:
+		order = Flush
+	}
+	return Score(uint64(order)<<24) | score, nil
+}
+
+// shortDeckWheelRanks returns the ranks of the lowest straight in a short deck, packed into 4 bits each, where the ace
+// is the lowest card
+func shortDeckWheelRanks() uint64 {
+	return uint64(Nine)<<16 | uint64(Eight)<<12 | uint64(Seven)<<8 | uint64(Six)<<4 | uint64(Ace)
+}
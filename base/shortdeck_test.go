@@ -0,0 +1,79 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestNewShortDeck(t *testing.T) {
+	cards := base.NewShortDeck().GetCards()
+	if len(cards) != 36 {
+		t.Fatalf("NewShortDeck() has %v cards, want 36", len(cards))
+	}
+	for _, c := range cards {
+		if c.GetRank() < base.Six {
+			t.Errorf("NewShortDeck() contains %v", c)
+		}
+	}
+}
+
+func TestShortDeckScore(t *testing.T) {
+	// From best to worst
+	hands := []struct {
+		cards string
+		rank  base.HandRank
+	}{
+		{"AsKsQsJsTs", base.StraightFlush},
+		{"9h8h7h6hAh", base.StraightFlush},
+		{"AsAdAcAh6s", base.FourOfAKind},
+		{"AsJs8s7s6s", base.Flush},
+		{"AsAdAcKhKs", base.FullHouse},
+		{"TsJdQcKhAs", base.Straight},
+		{"9s8d7c6hAs", base.Straight},
+		{"AsAdAc7h6s", base.ThreeOfAKind},
+		{"AsAdKcKh6s", base.TwoPair},
+		{"AsAd9c7h6s", base.Pair},
+		{"AsKdQcJh9s", base.HighCard},
+	}
+	previous := base.Score(1 << 32)
+	for _, hand := range hands {
+		cards, _ := base.ParseCards(hand.cards)
+		score, err := base.GetShortDeckScore(cards)
+		if err != nil {
+			t.Fatalf("GetShortDeckScore(%v) error = %v", hand.cards, err)
+		}
+		if score.GetHandRank() != hand.rank {
+			t.Errorf("GetShortDeckScore(%v) has rank %v, want %v", hand.cards, score.GetHandRank(), hand.rank)
+		}
+		if score >= previous {
+			t.Errorf("GetShortDeckScore(%v) = %x, want less than %x", hand.cards, score, previous)
+		}
+		previous = score
+	}
+}
+
+func TestShortDeckScoreSevenCards(t *testing.T) {
+	tests := []struct {
+		cards string
+		want  string
+	}{
+		{"As9d8c7h6sKsKd", "Straight, Nine high"},
+		{"As9d8c7h6sTs", "Straight, Ten high"},
+		{"As9s8s7s6sKsQs", "Straight flush, Nine high"},
+		{"AsAdAcKhKsQsJs", "Full house, Aces full of Kings"},
+		{"AsAdAcKsQsJs6s", "Flush, Ace high"},
+	}
+	for _, tt := range tests {
+		cards, _ := base.ParseCards(tt.cards)
+		score, _ := base.GetShortDeckScore(cards)
+		if got := score.GetDescription(); got != tt.want {
+			t.Errorf("GetShortDeckScore(%v) = %q, want %q", tt.cards, got, tt.want)
+		}
+	}
+
+	cards, _ := base.ParseCards("As2d8c7h6s")
+	if _, err := base.GetShortDeckScore(cards); err == nil {
+		t.Errorf("GetShortDeckScore() with a two succeeded, want error")
+	}
+}
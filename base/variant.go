@@ -0,0 +1,141 @@
+package base
+
+import "fmt"
+
+// Variant describes the rules of a variant of poker in which every player holds a hole of their own and shares a
+// board that is dealt over several streets
+type Variant interface {
+	// NewDeck returns a new unshuffled deck of the cards that the variant is played with
+	NewDeck() *Deck
+	// GetHoleSize returns the number of cards in every hole
+	GetHoleSize() int
+	// GetStreets returns the number of cards on the board after each street that deals to it, in order, e.g. 3, 4, and
+	// 5 for the flop, turn, and river
+	GetStreets() []int
+	// GetScore returns the score of the best poker hand that can be made from a hole and a board, which may be compared
+	// with the scores of other hands of the same variant
+	GetScore(hole []Card, board []Card) (Score, error)
+}
+
+// TexasHoldem is Texas Hold'em, where every hole has 2 cards and any 5 of the hole and board can be used
+type TexasHoldem struct{}
+
+// NewDeck returns a new unshuffled deck of 52 cards
+func (TexasHoldem) NewDeck() *Deck {
+	return NewDeck()
+}
+
+// GetHoleSize returns 2
+func (TexasHoldem) GetHoleSize() int {
+	return 2
+}
+
+// GetStreets returns the flop, turn, and river
+func (TexasHoldem) GetStreets() []int {
+	return []int{3, 4, 5}
+}
+
+// GetScore returns the same score as GetScore for the hole and board combined
+func (TexasHoldem) GetScore(hole []Card, board []Card) (Score, error) {
+	if len(hole) != 2 {
+		return 0, fmt.Errorf("only a hole of 2 cards can be scored in texas hold'em")
+	}
+	var hand [7]Card
+	n := copy(hand[:], hole)
+	n += copy(hand[n:], board)
+	return GetFastScore(hand[:n])
+}
+
+// ShortDeckHoldem is short-deck (6+) Hold'em, which is like Texas Hold'em but played with a short deck of 36 cards,
+// where A-6-7-8-9 is the lowest straight and a flush beats a full house
+type ShortDeckHoldem struct{}
+
+// NewDeck returns a new unshuffled short deck of 36 cards
+func (ShortDeckHoldem) NewDeck() *Deck {
+	return NewShortDeck()
+}
+
+// GetHoleSize returns 2
+func (ShortDeckHoldem) GetHoleSize() int {
+	return 2
+}
+
+// GetStreets returns the flop, turn, and river
+func (ShortDeckHoldem) GetStreets() []int {
+	return []int{3, 4, 5}
+}
+
+// GetScore returns the same score as GetShortDeckScore for the hole and board combined
+func (ShortDeckHoldem) GetScore(hole []Card, board []Card) (Score, error) {
+	if len(hole) != 2 {
+		return 0, fmt.Errorf("only a hole of 2 cards can be scored in short-deck hold'em")
+	}
+	var hand [7]Card
+	n := copy(hand[:], hole)
+	n += copy(hand[n:], board)
+	return GetShortDeckScore(hand[:n])
+}
+
+// Omaha is Omaha, where every hole has 4 cards and every hand must be made of exactly 2 cards from the hole and 3
+// from the board
+type Omaha struct{}
+
+// NewDeck returns a new unshuffled deck of 52 cards
+func (Omaha) NewDeck() *Deck {
+	return NewDeck()
+}
+
+// GetHoleSize returns 4
+func (Omaha) GetHoleSize() int {
+	return 4
+}
+
+// GetStreets returns the flop, turn, and river
+func (Omaha) GetStreets() []int {
+	return []int{3, 4, 5}
+}
+
+// GetScore returns the same score as GetOmahaScore
+func (Omaha) GetScore(hole []Card, board []Card) (Score, error) {
+	return GetOmahaScore(hole, board)
+}
+
+// FiveCardOmaha is like Omaha, but every hole has 5 cards
+type FiveCardOmaha struct{}
+
+// NewDeck returns a new unshuffled deck of 52 cards
+func (FiveCardOmaha) NewDeck() *Deck {
+	return NewDeck()
+}
+
+// GetHoleSize returns 5
+func (FiveCardOmaha) GetHoleSize() int {
+	return 5
+}
+
+// GetStreets returns the flop, turn, and river
+func (FiveCardOmaha) GetStreets() []int {
+	return []int{3, 4, 5}
+}
+
+// GetScore returns the same score as GetOmahaScore
+func (FiveCardOmaha) GetScore(hole []Card, board []Card) (Score, error) {
+	return GetOmahaScore(hole, board)
+}
+
+// GetBoardSize returns the number of cards on the board once every street of the variant has been dealt
+func GetBoardSize(v Variant) int {
+	streets := v.GetStreets()
+	return streets[len(streets)-1]
+}
+
+// IsStreet returns whether a board with the given number of cards has been dealt exactly up to some street of the
+// variant
+func IsStreet(v Variant, size int) bool {
+	for _, street := range v.GetStreets() {
+		if size == street {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package base_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		variant  base.Variant
+		deckSize int
+		hole     string
+		board    string
+		want     base.HandRank
+	}{
+		{base.TexasHoldem{}, 52, "AsKs", "QsJsTs2d3c", base.StraightFlush},
+		{base.ShortDeckHoldem{}, 36, "As6s", "7s8s9dKcKd", base.Straight},
+		{base.Omaha{}, 52, "AsKd2c3c", "QsJsTs9s", base.Straight},
+		{base.FiveCardOmaha{}, 52, "AsKd2c3c4c", "QsJsTs9s", base.Straight},
+	}
+	for _, tt := range tests {
+		if got := len(tt.variant.NewDeck().GetCards()); got != tt.deckSize {
+			t.Errorf("%T deck has %v cards, want %v", tt.variant, got, tt.deckSize)
+		}
+		if base.GetBoardSize(tt.variant) != 5 || !base.IsStreet(tt.variant, 3) || base.IsStreet(tt.variant, 2) {
+			t.Errorf("%T streets = %v, want the flop, turn, and river", tt.variant, tt.variant.GetStreets())
+		}
+		hole, _ := base.ParseCards(tt.hole)
+		board, _ := base.ParseCards(tt.board)
+		if len(hole) != tt.variant.GetHoleSize() {
+			t.Errorf("%T hole size = %v, want %v", tt.variant, tt.variant.GetHoleSize(), len(hole))
+		}
+		score, err := tt.variant.GetScore(hole, board)
+		if err != nil {
+			t.Fatalf("%T GetScore() error = %v", tt.variant, err)
+		}
+		if score.GetHandRank() != tt.want {
+			t.Errorf("%T GetScore() = %v, want %v", tt.variant, score.GetHandRank(), tt.want)
+		}
+	}
+}
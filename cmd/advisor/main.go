@@ -0,0 +1,188 @@
+// Command advisor prints the outcomes of a hole against random opponents, given the board and any dead cards, and
+// whether to call a bet given the pot, the amount to call, and the effective stack.
+//
+// Usage:
+//
+//	advisor -hole AsKd [-board 2c7dKh] [-opponents 2] [-dead 9s] [-pot 30 -call 10 -stack 200] [-json]
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+// advice is everything the advisor reports
+type advice struct {
+	Hole      []string `json:"hole"`
+	Board     []string `json:"board"`
+	Dead      []string `json:"dead,omitempty"`
+	Opponents int      `json:"opponents"`
+	// Hand is a description of the hand made by the hole and board, if the board has been dealt
+	Hand string `json:"hand,omitempty"`
+	// Best is the 5 cards that make the hand, if the board has been dealt
+	Best []string `json:"best,omitempty"`
+	// Current is the current order of the hole among all possible opponent holes, if the board has been dealt
+	Current *current           `json:"current,omitempty"`
+	Outcome prediction.Outcome `json:"outcome"`
+	// Estimated is whether the outcome was estimated by sampling rather than computed exactly
+	Estimated bool `json:"estimated,omitempty"`
+	// Call is the advice for calling a bet, if a pot was given
+	Call *prediction.CallAdvice `json:"call,omitempty"`
+}
+
+type current struct {
+	// Rank is the rank of the hole among all possible holes, where 1 is the best and tied holes share a rank
+	Rank    int64              `json:"rank"`
+	Better  int64              `json:"better"`
+	Same    int64              `json:"same"`
+	Worse   int64              `json:"worse"`
+	Total   int64              `json:"total"`
+	Outcome prediction.Outcome `json:"outcome"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("advisor: ")
+
+	holeFlag := flag.String("hole", "", "the 2 cards in the hole, e.g. AsKd")
+	boardFlag := flag.String("board", "", "the 0 or 3 to 5 cards on the board, e.g. 2c7dKh")
+	deadFlag := flag.String("dead", "", "cards known not to be in the deck, e.g. 9s")
+	opponentsFlag := flag.Int("opponents", 1, "the number of opponents")
+	potFlag := flag.Int64("pot", 0, "the pot, including the bet to call")
+	callFlag := flag.Int64("call", 0, "the amount to call")
+	stackFlag := flag.Int64("stack", 0, "the effective stack before calling")
+	jsonFlag := flag.Bool("json", false, "print the advice as JSON")
+	flag.Parse()
+
+	hole, err := base.ParseCards(*holeFlag)
+	if err != nil {
+		log.Fatalf("invalid hole: %v", err)
+	}
+	board, err := base.ParseCards(*boardFlag)
+	if err != nil {
+		log.Fatalf("invalid board: %v", err)
+	}
+	dead, err := base.ParseCards(*deadFlag)
+	if err != nil {
+		log.Fatalf("invalid dead cards: %v", err)
+	}
+	known := base.NewCardSet(hole...)
+	for _, cards := range [][]base.Card{board, dead} {
+		set := base.NewCardSet(cards...)
+		if known.Overlaps(set) {
+			log.Fatalf("hole, board, and dead cards overlap")
+		}
+		known = known.Union(set)
+	}
+
+	a, err := advise(hole, board, dead, *opponentsFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *potFlag > 0 {
+		bet := prediction.Bet{Pot: *potFlag, ToCall: *callFlag, Stack: *stackFlag}
+		call, err := prediction.AdviseCall(a.Outcome, bet)
+		if err != nil {
+			log.Fatalf("invalid bet: %v", err)
+		}
+		a.Call = &call
+	}
+
+	if *jsonFlag {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(a); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	printAdvice(a)
+}
+
+func advise(hole, board, dead []base.Card, opponents int) (*advice, error) {
+	options := prediction.Options{Opponents: opponents, Dead: dead}
+	a := &advice{
+		Hole:      formatCards(hole),
+		Board:     formatCards(board),
+		Dead:      formatCards(dead),
+		Opponents: opponents,
+	}
+
+	if len(board) == 0 {
+		// The precomputed initial outcomes assume a full deck, so they are sampled instead once cards are dead
+		var err error
+		a.Outcome, err = prediction.GetInitialOutcomes(hole, options)
+		a.Estimated = len(dead) > 0
+		return a, err
+	}
+
+	hand := append(append([]base.Card{}, hole...), board...)
+	score, err := base.GetScore(hand)
+	if err != nil {
+		return nil, err
+	}
+	best, err := base.GetBestFive(hand)
+	if err != nil {
+		return nil, err
+	}
+	a.Hand, a.Best = score.GetDescription(), formatCards(best)
+
+	_, better, same, worse, total, outcome, err := prediction.GetCurrentOrder(hole, board, options)
+	if err != nil {
+		return nil, err
+	}
+	a.Current = &current{better + 1, better, same, worse, total, outcome}
+
+	// Future outcomes are only exact against a single opponent, and sampled against more
+	a.Outcome, err = prediction.GetFutureOutcomes(hole, board, options)
+	a.Estimated = opponents > 1
+	return a, err
+}
+
+func printAdvice(a *advice) {
+	fmt.Printf("Hole:      %v\n", strings.Join(a.Hole, " "))
+	if len(a.Board) > 0 {
+		fmt.Printf("Board:     %v\n", strings.Join(a.Board, " "))
+	}
+	if len(a.Dead) > 0 {
+		fmt.Printf("Dead:      %v\n", strings.Join(a.Dead, " "))
+	}
+	fmt.Printf("Opponents: %v\n", a.Opponents)
+	if a.Hand != "" {
+		fmt.Printf("Hand:      %v (%v)\n", a.Hand, strings.Join(a.Best, " "))
+	}
+	if a.Current != nil {
+		fmt.Printf("Current:   rank %v of %v possible holes (%v better, %v same, %v worse)\n",
+			a.Current.Rank, a.Current.Total, a.Current.Better, a.Current.Same, a.Current.Worse)
+		fmt.Printf("           %v\n", formatOutcome(a.Current.Outcome))
+	}
+	estimated := ""
+	if a.Estimated {
+		estimated = " (estimated)"
+	}
+	fmt.Printf("Final:     %v%v\n", formatOutcome(a.Outcome), estimated)
+	if a.Call != nil {
+		fmt.Printf("Advice:    %v, EV %+.2f\n", a.Call.Recommendation, a.Call.EV)
+		fmt.Printf("           %v\n", a.Call.Reason)
+	}
+}
+
+func formatOutcome(o prediction.Outcome) string {
+	return fmt.Sprintf("win %.2f%%, tie %.2f%%, lose %.2f%%, equity %.2f%%", 100*o.Win, 100*o.Tie, 100*o.Lose,
+		100*o.Equity)
+}
+
+func formatCards(cards []base.Card) []string {
+	result := make([]string, len(cards))
+	for i, c := range cards {
+		result[i] = c.String()
+	}
+	return result
+}
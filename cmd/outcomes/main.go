@@ -0,0 +1,117 @@
+// Command outcomes regenerates and verifies the precomputed initial outcome tables used by GetInitialOutcomes, which
+// are embedded from prediction/data.
+//
+// Usage:
+//
+//	outcomes generate -opponents 2 -seed 1 -out prediction/data/initial_outcomes_2
+//	outcomes verify prediction/data/initial_outcomes_1 [prediction/data/initial_outcomes_2 ...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags)
+	log.SetPrefix("outcomes: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "generate":
+		generate(os.Args[2:])
+	case "verify":
+		verify(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage:\n")
+	fmt.Fprintf(os.Stderr, "  outcomes generate -opponents n [-seed n] -out path\n")
+	fmt.Fprintf(os.Stderr, "  outcomes verify path...\n")
+	os.Exit(2)
+}
+
+// generate regenerates the initial outcomes for a number of opponents, logging progress as it goes
+func generate(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	opponents := flags.Int("opponents", 1, fmt.Sprintf("the number of opponents, from 1 to %v", prediction.MaxOpponents))
+	seed := flags.Int64("seed", 1, "the seed for the games simulated against more than 1 opponent")
+	out := flags.String("out", "", "the path to write the initial outcomes to")
+	flags.Parse(args)
+	if *out == "" {
+		log.Fatal("generate requires -out")
+	}
+	if *opponents < 1 || *opponents > prediction.MaxOpponents {
+		log.Fatalf("-opponents must be from 1 to %v", prediction.MaxOpponents)
+	}
+
+	// Write to a temporary file that only replaces the output once it is complete, so that a failure never leaves a
+	// truncated table behind
+	file, err := ioutil.TempFile(filepath.Dir(*out), filepath.Base(*out)+".*")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fail := func(err error) {
+		file.Close()
+		os.Remove(file.Name())
+		log.Fatal(err)
+	}
+
+	log.Printf("generating initial outcomes for %v opponents with seed %v to %v", *opponents, *seed, *out)
+	if err := prediction.PrecomputeInitialOutcomes(*opponents, *seed, file); err != nil {
+		fail(err)
+	}
+	if err := file.Chmod(0644); err != nil {
+		fail(err)
+	}
+	if err := file.Sync(); err != nil {
+		fail(err)
+	}
+	if err := file.Close(); err != nil {
+		fail(err)
+	}
+	if err := os.Rename(file.Name(), *out); err != nil {
+		fail(err)
+	}
+	log.Printf("done")
+}
+
+// verify verifies initial outcomes files, printing every problem found and exiting with an error if there are any
+func verify(paths []string) {
+	if len(paths) == 0 {
+		usage()
+	}
+
+	failed := false
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		problems := prediction.VerifyInitialOutcomes(file)
+		file.Close()
+
+		for _, problem := range problems {
+			fmt.Printf("%v: %v\n", path, problem)
+		}
+		if len(problems) > 0 {
+			failed = true
+		} else {
+			fmt.Printf("%v: ok\n", path)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
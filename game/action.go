@@ -0,0 +1,77 @@
+package game
+
+// Street is a round of a hand.
+type Street int
+
+const (
+	Preflop  Street = iota + 1 // holes are dealt and the blinds are posted
+	Flop                       // the first 3 cards of the board are dealt
+	Turn                       // the 4th card of the board is dealt
+	River                      // the 5th card of the board is dealt
+	Showdown                   // betting is over and the remaining holes are compared
+)
+
+func (s Street) String() string {
+	switch s {
+	case Preflop:
+		return "Preflop"
+	case Flop:
+		return "Flop"
+	case Turn:
+		return "Turn"
+	case River:
+		return "River"
+	case Showdown:
+		return "Showdown"
+	}
+	return "(invalid)"
+}
+
+// ActionType is a kind of action taken by a player.
+type ActionType int
+
+const (
+	Fold           ActionType = iota + 1 // give up the hand
+	Check                                // pass when there is nothing to call
+	Call                                 // match the current bet, or go all-in for less
+	Bet                                  // make the first bet on a street
+	Raise                                // increase the current bet
+	PostAnte                             // forced ante, posted by the hand itself
+	PostSmallBlind                       // forced small blind, posted by the hand itself
+	PostBigBlind                         // forced big blind, posted by the hand itself
+)
+
+func (t ActionType) String() string {
+	switch t {
+	case Fold:
+		return "Fold"
+	case Check:
+		return "Check"
+	case Call:
+		return "Call"
+	case Bet:
+		return "Bet"
+	case Raise:
+		return "Raise"
+	case PostAnte:
+		return "Post ante"
+	case PostSmallBlind:
+		return "Post small blind"
+	case PostBigBlind:
+		return "Post big blind"
+	}
+	return "(invalid)"
+}
+
+// Action is an action taken by the player in a seat.
+type Action struct {
+	Seat int
+	Type ActionType
+	// Amount is, for a bet or raise, the total that the player has bet on the street after it, i.e. a raise is a raise
+	// "to" the amount. Once the action is taken, it is also filled in with the chips put in by a call or forced bet.
+	Amount int64
+	// Street is the street that the action was taken on, filled in once the action is taken.
+	Street Street
+	// AllIn is whether the action put the player all-in, filled in once the action is taken.
+	AllIn bool
+}
@@ -0,0 +1,528 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// Player is a player sitting down to a hand.
+type Player struct {
+	Name  string
+	Stack int64
+}
+
+// Config configures a single hand of No-Limit Texas Hold'em.
+type Config struct {
+	// Players are the players in seat order, from seat 0.
+	Players []Player
+	// Button is the seat of the dealer button.
+	Button     int
+	SmallBlind int64
+	BigBlind   int64
+	// Ante is posted by every player before the blinds, or not at all if zero.
+	Ante int64
+	// Deck is the deck to deal from, in order. If nil, a new shuffled deck is used.
+	Deck *base.Deck
+	// OddChips is the house rule for chips that cannot be split evenly at the showdown.
+	OddChips OddChipRule
+}
+
+// Seat is the state of a player during a hand.
+type Seat struct {
+	Name string
+	// Stack is the number of chips the player has behind, i.e. not yet put into the pot.
+	Stack int64
+	Hole  []base.Card
+	// Bet is the number of chips the player has put in on the current street.
+	Bet int64
+	// Contributed is the number of chips the player has put into the pot over the whole hand, including antes and
+	// blinds.
+	Contributed int64
+	Folded      bool
+	AllIn       bool
+}
+
+// Hand is a single hand of No-Limit Texas Hold'em, from posting the blinds to the showdown. Players act in turn by
+// calling Act, which only accepts legal actions, and streets advance on their own once betting on them is over.
+type Hand struct {
+	config  Config
+	deck    *base.Deck
+	seats   []Seat
+	board   []base.Card
+	street  Street
+	actions []Action
+
+	toAct      int
+	currentBet int64 // the highest bet on the current street
+	minRaise   int64 // the size of the last full bet or raise on the current street
+	// pending is whether each seat still has to act on the current street
+	pending []bool
+	// closed is whether each seat has acted since the last full bet or raise, in which case it cannot raise again
+	closed []bool
+	over   bool
+	// payouts are the chips won by each seat once the hand is over
+	payouts []int64
+	pots    []Pot
+}
+
+// NewHand starts a new hand by posting the antes and blinds and dealing a hole of 2 cards to every player, after which
+// the first player is to act.
+func NewHand(config Config) (*Hand, error) {
+	if len(config.Players) < 2 {
+		return nil, fmt.Errorf("a hand needs at least 2 players")
+	}
+	if config.Button < 0 || config.Button >= len(config.Players) {
+		return nil, fmt.Errorf("button %v is not a seat", config.Button)
+	}
+	if config.SmallBlind <= 0 || config.BigBlind < config.SmallBlind {
+		return nil, fmt.Errorf("blinds must be positive, and the big blind must be at least the small blind")
+	}
+	if config.Ante < 0 {
+		return nil, fmt.Errorf("ante cannot be negative")
+	}
+
+	h := &Hand{
+		config:   config,
+		deck:     config.Deck,
+		seats:    make([]Seat, len(config.Players)),
+		board:    []base.Card{},
+		street:   Preflop,
+		pending:  make([]bool, len(config.Players)),
+		closed:   make([]bool, len(config.Players)),
+		minRaise: config.BigBlind,
+	}
+	if h.deck == nil {
+		h.deck = base.NewDeck()
+		h.deck.Shuffle()
+	}
+	// Every hole, the board, and a burn card before each street may have to be dealt
+	if needed := 2*len(config.Players) + 8; len(h.deck.GetCards()) < needed {
+		return nil, fmt.Errorf("deck has %v cards, but %v players need %v", len(h.deck.GetCards()),
+			len(config.Players), needed)
+	}
+	for i, p := range config.Players {
+		if p.Stack <= 0 {
+			return nil, fmt.Errorf("player %q in seat %v has no chips", p.Name, i)
+		}
+		h.seats[i] = Seat{Name: p.Name, Stack: p.Stack}
+	}
+
+	// Antes are dead money, so they go into the pot without counting towards any bet
+	if config.Ante > 0 {
+		for i := range h.seats {
+			amount := h.put(i, config.Ante)
+			h.seats[i].Bet -= amount
+			h.record(Action{Seat: i, Type: PostAnte, Amount: amount})
+		}
+	}
+
+	// Heads-up, the button posts the small blind
+	smallBlind := h.next(config.Button)
+	if len(h.seats) == 2 {
+		smallBlind = config.Button
+	}
+	bigBlind := h.next(smallBlind)
+	// A seat put all-in by the ante has nothing left to post
+	if !h.seats[smallBlind].AllIn {
+		h.record(Action{Seat: smallBlind, Type: PostSmallBlind, Amount: h.put(smallBlind, config.SmallBlind)})
+	}
+	if !h.seats[bigBlind].AllIn {
+		h.record(Action{Seat: bigBlind, Type: PostBigBlind, Amount: h.put(bigBlind, config.BigBlind)})
+	}
+	h.currentBet = config.BigBlind
+
+	// Deal one card at a time, starting to the left of the button
+	for round := 0; round < 2; round++ {
+		for i, seat := 0, h.next(config.Button); i < len(h.seats); i, seat = i+1, h.next(seat) {
+			c, err := h.deck.Next()
+			if err != nil {
+				return nil, err
+			}
+			h.seats[seat].Hole = append(h.seats[seat].Hole, c)
+		}
+	}
+
+	for i := range h.seats {
+		h.pending[i] = !h.seats[i].AllIn
+	}
+	h.toAct = bigBlind
+	if err := h.advance(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// GetStreet returns the current street.
+func (h *Hand) GetStreet() Street {
+	return h.street
+}
+
+// GetBoard returns the cards dealt to the board so far.
+func (h *Hand) GetBoard() []base.Card {
+	return append([]base.Card{}, h.board...)
+}
+
+// GetSeats returns the state of every seat.
+func (h *Hand) GetSeats() []Seat {
+	result := make([]Seat, len(h.seats))
+	for i, s := range h.seats {
+		result[i] = s
+		result[i].Hole = append([]base.Card{}, s.Hole...)
+	}
+	return result
+}
+
+// GetConfig returns the configuration that the hand was started with.
+func (h *Hand) GetConfig() Config {
+	return h.config
+}
+
+// GetActions returns every action taken so far, including the forced antes and blinds.
+func (h *Hand) GetActions() []Action {
+	return append([]Action{}, h.actions...)
+}
+
+// GetPot returns the total number of chips in the pot, including bets on the current street.
+func (h *Hand) GetPot() int64 {
+	pot := int64(0)
+	for _, s := range h.seats {
+		pot += s.Contributed
+	}
+	return pot
+}
+
+// GetOpponents returns the number of players other than the one in the seat who have not folded.
+func (h *Hand) GetOpponents(seat int) int {
+	result := 0
+	for i, s := range h.seats {
+		if i != seat && !s.Folded {
+			result++
+		}
+	}
+	return result
+}
+
+// GetPayouts returns the number of chips won by every seat, which have already been added to their stacks, or nil if
+// the hand is not over.
+func (h *Hand) GetPayouts() []int64 {
+	if !h.over {
+		return nil
+	}
+	return append([]int64{}, h.payouts...)
+}
+
+// GetPots returns the main pot and side pots, along with who won them, or nil if the hand is not over.
+func (h *Hand) GetPots() []Pot {
+	if !h.over {
+		return nil
+	}
+	return append([]Pot{}, h.pots...)
+}
+
+// IsOver returns whether the hand is over, either because every player but one has folded or because it has reached
+// the showdown.
+func (h *Hand) IsOver() bool {
+	return h.over
+}
+
+// GetToAct returns the seat of the player who is to act, or -1 if the hand is over.
+func (h *Hand) GetToAct() int {
+	if h.over {
+		return -1
+	}
+	return h.toAct
+}
+
+// GetToCall returns the number of chips the player who is to act must put in to call, limited by their stack.
+func (h *Hand) GetToCall() int64 {
+	if h.over {
+		return 0
+	}
+	s := h.seats[h.toAct]
+	if h.currentBet-s.Bet > s.Stack {
+		return s.Stack
+	}
+	return h.currentBet - s.Bet
+}
+
+// GetMinRaise returns the smallest total that the player who is to act may bet or raise to on the current street,
+// which is less than a full bet or raise only if it puts them all-in.
+func (h *Hand) GetMinRaise() int64 {
+	if h.over {
+		return 0
+	}
+	s := h.seats[h.toAct]
+	if h.currentBet+h.minRaise > s.Bet+s.Stack {
+		return s.Bet + s.Stack
+	}
+	return h.currentBet + h.minRaise
+}
+
+// GetMaxRaise returns the largest total that the player who is to act may bet or raise to on the current street, i.e.
+// all-in.
+func (h *Hand) GetMaxRaise() int64 {
+	if h.over {
+		return 0
+	}
+	s := h.seats[h.toAct]
+	return s.Bet + s.Stack
+}
+
+// GetLegalActions returns the types of action that the player who is to act may take.
+func (h *Hand) GetLegalActions() []ActionType {
+	if h.over {
+		return []ActionType{}
+	}
+	s := h.seats[h.toAct]
+	result := []ActionType{Fold}
+	if s.Bet == h.currentBet {
+		result = append(result, Check)
+	} else {
+		result = append(result, Call)
+	}
+	if h.currentBet == 0 {
+		result = append(result, Bet)
+	} else if s.Stack > h.currentBet-s.Bet && !h.closed[h.toAct] {
+		result = append(result, Raise)
+	}
+	return result
+}
+
+// Act takes an action for the player who is to act, returning an error without changing anything if the action is not
+// legal. Bets and raises are made to the total amount of the action.
+func (h *Hand) Act(action Action) error {
+	if h.over {
+		return fmt.Errorf("hand is over")
+	}
+	if action.Seat != h.toAct {
+		return fmt.Errorf("seat %v is to act, not seat %v", h.toAct, action.Seat)
+	}
+	seat := action.Seat
+	s := &h.seats[seat]
+	toCall := h.currentBet - s.Bet
+
+	switch action.Type {
+	case Fold:
+		s.Folded = true
+	case Check:
+		if toCall > 0 {
+			return fmt.Errorf("cannot check facing a bet of %v", h.currentBet)
+		}
+	case Call:
+		if toCall == 0 {
+			return fmt.Errorf("cannot call when there is no bet to call")
+		}
+		action.Amount = h.put(seat, toCall)
+	case Bet:
+		if h.currentBet > 0 {
+			return fmt.Errorf("cannot bet facing a bet of %v, raise instead", h.currentBet)
+		}
+		if err := h.checkRaise(seat, action.Amount); err != nil {
+			return err
+		}
+		h.raise(seat, action.Amount)
+	case Raise:
+		if h.currentBet == 0 {
+			return fmt.Errorf("cannot raise when there is no bet, bet instead")
+		}
+		if h.closed[seat] {
+			return fmt.Errorf("cannot raise since betting was not reopened by a full raise")
+		}
+		if action.Amount <= h.currentBet {
+			return fmt.Errorf("cannot raise to %v, which is not more than the bet of %v", action.Amount, h.currentBet)
+		}
+		if err := h.checkRaise(seat, action.Amount); err != nil {
+			return err
+		}
+		h.raise(seat, action.Amount)
+	default:
+		return fmt.Errorf("%v is not an action that players can take", action.Type)
+	}
+
+	h.pending[seat] = false
+	h.closed[seat] = true
+	h.record(action)
+	return h.advance()
+}
+
+// checkRaise returns an error if the seat cannot bet or raise to the total amount.
+func (h *Hand) checkRaise(seat int, amount int64) error {
+	s := h.seats[seat]
+	if amount > s.Bet+s.Stack {
+		return fmt.Errorf("cannot bet or raise to %v with only %v", amount, s.Bet+s.Stack)
+	}
+	if amount < h.currentBet+h.minRaise && amount != s.Bet+s.Stack {
+		return fmt.Errorf("cannot bet or raise to %v, the minimum is %v unless all-in", amount,
+			h.currentBet+h.minRaise)
+	}
+	return nil
+}
+
+// raise bets or raises the seat to the total amount, reopening the betting for everyone else if it is a full raise.
+func (h *Hand) raise(seat int, amount int64) {
+	increment := amount - h.currentBet
+	h.put(seat, amount-h.seats[seat].Bet)
+	full := increment >= h.minRaise
+	if full {
+		h.minRaise = increment
+	}
+	h.currentBet = amount
+
+	for i, s := range h.seats {
+		if i == seat || s.Folded || s.AllIn {
+			continue
+		}
+		// Everyone has to respond to the new bet, but only a full raise lets those who have acted raise again
+		h.pending[i] = true
+		if full {
+			h.closed[i] = false
+		}
+	}
+}
+
+// put moves up to the given number of chips from the seat's stack into the pot, and returns how many were moved.
+func (h *Hand) put(seat int, amount int64) int64 {
+	s := &h.seats[seat]
+	if amount > s.Stack {
+		amount = s.Stack
+	}
+	s.Stack -= amount
+	s.Bet += amount
+	s.Contributed += amount
+	if s.Stack == 0 {
+		s.AllIn = true
+	}
+	return amount
+}
+
+// record adds an action to the history, filling in the street and whether it put the player all-in.
+func (h *Hand) record(action Action) {
+	action.Street = h.street
+	action.AllIn = h.seats[action.Seat].AllIn && action.Type != Fold && action.Type != Check
+	h.actions = append(h.actions, action)
+}
+
+// next returns the seat to the left of the given seat.
+func (h *Hand) next(seat int) int {
+	return (seat + 1) % len(h.seats)
+}
+
+// advance moves the turn to the next player who has to act, ending the street or the hand if nobody does.
+func (h *Hand) advance() error {
+	remaining, canAct := 0, 0
+	for _, s := range h.seats {
+		if !s.Folded {
+			remaining++
+		}
+		if !s.Folded && !s.AllIn {
+			canAct++
+		}
+	}
+	if remaining == 1 {
+		h.returnUncalledBet()
+		h.finish()
+		return nil
+	}
+
+	// A lone player who can still act only has to if they have a bet to call
+	if canAct == 1 {
+		for i, s := range h.seats {
+			if !s.Folded && !s.AllIn && s.Bet >= h.currentBet {
+				h.pending[i] = false
+			}
+		}
+	}
+	for i, seat := 0, h.next(h.toAct); i < len(h.seats); i, seat = i+1, h.next(seat) {
+		if h.pending[seat] {
+			h.toAct = seat
+			return nil
+		}
+	}
+
+	// Betting on this street is over, so either move on to the next street or run out the rest of the board if
+	// nobody can bet anymore
+	h.returnUncalledBet()
+	for {
+		for i := range h.seats {
+			h.seats[i].Bet = 0
+		}
+		if h.street == River {
+			h.street = Showdown
+			h.finish()
+			return nil
+		}
+		if err := h.deal(); err != nil {
+			return err
+		}
+		if canAct >= 2 {
+			break
+		}
+	}
+
+	h.currentBet, h.minRaise = 0, h.config.BigBlind
+	for i, s := range h.seats {
+		h.pending[i] = !s.Folded && !s.AllIn
+		h.closed[i] = false
+	}
+	h.toAct = h.config.Button
+	return h.advance()
+}
+
+// finish ends the hand, awarding the pots to the winners.
+func (h *Hand) finish() {
+	contenders := make([]Contender, len(h.seats))
+	for i, s := range h.seats {
+		contenders[i] = Contender{s.Hole, s.Contributed, s.Folded}
+	}
+	// The hand always reaches the river before a showdown, so this cannot fail
+	h.payouts, h.pots, _ = ResolveShowdown(contenders, h.board, h.config.Button, h.config.OddChips)
+	for i, payout := range h.payouts {
+		h.seats[i].Stack += payout
+	}
+	h.over = true
+}
+
+// deal burns a card and deals the board for the next street.
+func (h *Hand) deal() error {
+	cards := 1
+	if h.street == Preflop {
+		cards = 3
+	}
+	if _, err := h.deck.Next(); err != nil {
+		return err
+	}
+	for i := 0; i < cards; i++ {
+		c, err := h.deck.Next()
+		if err != nil {
+			return err
+		}
+		h.board = append(h.board, c)
+	}
+	h.street++
+	return nil
+}
+
+// returnUncalledBet returns the part of the highest bet on the street that nobody else matched to the player who bet
+// it.
+func (h *Hand) returnUncalledBet() {
+	highest := 0
+	for i, s := range h.seats {
+		if s.Bet > h.seats[highest].Bet {
+			highest = i
+		}
+	}
+	second := int64(0)
+	for i, s := range h.seats {
+		if i != highest && s.Bet > second {
+			second = s.Bet
+		}
+	}
+	if excess := h.seats[highest].Bet - second; excess > 0 {
+		s := &h.seats[highest]
+		s.Bet -= excess
+		s.Contributed -= excess
+		s.Stack += excess
+		s.AllIn = s.Stack == 0
+	}
+}
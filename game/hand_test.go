@@ -0,0 +1,228 @@
+package game_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+// newHand starts a hand dealt from an unshuffled deck, so that the cards are always the same.
+func newHand(t *testing.T, stacks []int64, button int, ante int64) *game.Hand {
+	t.Helper()
+	players := make([]game.Player, len(stacks))
+	for i, stack := range stacks {
+		players[i] = game.Player{Name: string(rune('A' + i)), Stack: stack}
+	}
+	h, err := game.NewHand(game.Config{Players: players, Button: button, SmallBlind: 1, BigBlind: 2, Ante: ante,
+		Deck: base.NewDeck()})
+	if err != nil {
+		t.Fatalf("NewHand() error = %v", err)
+	}
+	return h
+}
+
+// players returns the given number of players, each with the same stack.
+func players(n int, stack int64) []game.Player {
+	result := make([]game.Player, n)
+	for i := range result {
+		result[i] = game.Player{Name: string(rune('A' + i)), Stack: stack}
+	}
+	return result
+}
+
+func act(t *testing.T, h *game.Hand, seat int, actionType game.ActionType, amount int64) {
+	t.Helper()
+	if err := h.Act(game.Action{Seat: seat, Type: actionType, Amount: amount}); err != nil {
+		t.Fatalf("Act(%v %v %v) error = %v", seat, actionType, amount, err)
+	}
+}
+
+func stacks(h *game.Hand) []int64 {
+	result := []int64{}
+	for _, s := range h.GetSeats() {
+		result = append(result, s.Stack)
+	}
+	return result
+}
+
+func TestHeadsUp(t *testing.T) {
+	h := newHand(t, []int64{100, 100}, 0, 0)
+
+	// The button posts the small blind and acts first preflop
+	if got := h.GetToAct(); got != 0 {
+		t.Fatalf("GetToAct() = %v, want 0", got)
+	}
+	if got := h.GetPot(); got != 3 {
+		t.Errorf("GetPot() = %v, want 3", got)
+	}
+	actions := []game.ActionType{game.Fold, game.Call, game.Raise}
+	if got := h.GetLegalActions(); !reflect.DeepEqual(got, actions) {
+		t.Errorf("GetLegalActions() = %v, want %v", got, actions)
+	}
+	// Holes are dealt one card at a time starting to the left of the button
+	seats := h.GetSeats()
+	want := []base.Card{base.NewCard(base.Two, base.Club), base.NewCard(base.Two, base.Heart)}
+	if got := seats[1].Hole; !reflect.DeepEqual(got, want) {
+		t.Errorf("seat 1 hole = %v, want %v", got, want)
+	}
+	act(t, h, 0, game.Call, 0)
+
+	// The big blind has the option
+	if err := h.Act(game.Action{Seat: 0, Type: game.Check}); err == nil {
+		t.Errorf("Act() out of turn succeeded, want error")
+	}
+	act(t, h, 1, game.Check, 0)
+
+	// The flop is dealt after burning a card, and the big blind acts first after the flop
+	if got := h.GetStreet(); got != game.Flop {
+		t.Fatalf("GetStreet() = %v, want %v", got, game.Flop)
+	}
+	if got, want := h.GetBoard(), []base.Card{base.NewCard(base.Three, base.Diamond),
+		base.NewCard(base.Three, base.Heart), base.NewCard(base.Three, base.Spade)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetBoard() = %v, want %v", got, want)
+	}
+	if got := h.GetToAct(); got != 1 {
+		t.Errorf("GetToAct() = %v, want 1", got)
+	}
+	if err := h.Act(game.Action{Seat: 1, Type: game.Bet, Amount: 1}); err == nil {
+		t.Errorf("Act() bet below the big blind succeeded, want error")
+	}
+	act(t, h, 1, game.Bet, 4)
+	act(t, h, 0, game.Fold, 0)
+
+	if !h.IsOver() {
+		t.Fatalf("IsOver() = false, want true")
+	}
+	if got, want := stacks(h), []int64{98, 102}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stacks = %v, want %v", got, want)
+	}
+}
+
+func TestMinRaise(t *testing.T) {
+	h := newHand(t, []int64{100, 100, 100}, 0, 0)
+
+	// The button is first to act with 3 players
+	if err := h.Act(game.Action{Seat: 0, Type: game.Raise, Amount: 3}); err == nil {
+		t.Errorf("Act() raise to 3 succeeded, want error")
+	}
+	if got := h.GetMinRaise(); got != 4 {
+		t.Errorf("GetMinRaise() = %v, want 4", got)
+	}
+	act(t, h, 0, game.Raise, 6)
+
+	// The last raise was 4, so the next raise must be to at least 10
+	if got := h.GetMinRaise(); got != 10 {
+		t.Errorf("GetMinRaise() = %v, want 10", got)
+	}
+	if err := h.Act(game.Action{Seat: 1, Type: game.Raise, Amount: 9}); err == nil {
+		t.Errorf("Act() raise to 9 succeeded, want error")
+	}
+	if err := h.Act(game.Action{Seat: 1, Type: game.Check}); err == nil {
+		t.Errorf("Act() check facing a raise succeeded, want error")
+	}
+	act(t, h, 1, game.Raise, 10)
+	act(t, h, 2, game.Call, 0)
+	act(t, h, 0, game.Call, 0)
+
+	if got := h.GetStreet(); got != game.Flop {
+		t.Errorf("GetStreet() = %v, want %v", got, game.Flop)
+	}
+	if got := h.GetPot(); got != 30 {
+		t.Errorf("GetPot() = %v, want 30", got)
+	}
+	// The small blind acts first after the flop
+	if got := h.GetToAct(); got != 1 {
+		t.Errorf("GetToAct() = %v, want 1", got)
+	}
+}
+
+func TestIncompleteRaise(t *testing.T) {
+	h := newHand(t, []int64{100, 100, 13}, 0, 0)
+
+	act(t, h, 0, game.Raise, 8)
+	act(t, h, 1, game.Call, 0)
+	// The big blind goes all-in for 5 more, which is less than a full raise of 6
+	act(t, h, 2, game.Raise, 13)
+
+	// Players who already acted can only call or fold
+	if got, want := h.GetLegalActions(), []game.ActionType{game.Fold, game.Call}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetLegalActions() = %v, want %v", got, want)
+	}
+	if err := h.Act(game.Action{Seat: 0, Type: game.Raise, Amount: 30}); err == nil {
+		t.Errorf("Act() raise after an incomplete raise succeeded, want error")
+	}
+	act(t, h, 0, game.Call, 0)
+	act(t, h, 1, game.Call, 0)
+	if got := h.GetStreet(); got != game.Flop {
+		t.Errorf("GetStreet() = %v, want %v", got, game.Flop)
+	}
+}
+
+func TestAllIn(t *testing.T) {
+	h := newHand(t, []int64{50, 100, 100}, 0, 1)
+
+	if got := h.GetPot(); got != 6 {
+		t.Errorf("GetPot() = %v, want 6", got)
+	}
+	act(t, h, 0, game.Raise, 49)
+	act(t, h, 1, game.Raise, 99)
+	act(t, h, 2, game.Fold, 0)
+
+	// Nobody can bet anymore, so the board is run out, and the uncalled part of the last raise is returned
+	if !h.IsOver() {
+		t.Fatalf("IsOver() = false, want true")
+	}
+	if got := h.GetStreet(); got != game.Showdown {
+		t.Errorf("GetStreet() = %v, want %v", got, game.Showdown)
+	}
+	if got := len(h.GetBoard()); got != 5 {
+		t.Errorf("GetBoard() has %v cards, want 5", got)
+	}
+	// Seat 0 makes fours full of threes against fours full of twos
+	if got, want := h.GetPayouts(), []int64{103, 0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPayouts() = %v, want %v", got, want)
+	}
+	if got, want := stacks(h), []int64{103, 50, 97}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stacks = %v, want %v", got, want)
+	}
+	if got := h.GetPot(); got != 103 {
+		t.Errorf("GetPot() = %v, want 103", got)
+	}
+}
+
+func TestAllInFromAnte(t *testing.T) {
+	h := newHand(t, []int64{100, 100, 1}, 0, 1)
+
+	// The big blind is all-in from the ante, so it posts nothing
+	want := []game.Action{
+		{Seat: 0, Type: game.PostAnte, Amount: 1, Street: game.Preflop},
+		{Seat: 1, Type: game.PostAnte, Amount: 1, Street: game.Preflop},
+		{Seat: 2, Type: game.PostAnte, Amount: 1, Street: game.Preflop, AllIn: true},
+		{Seat: 1, Type: game.PostSmallBlind, Amount: 1, Street: game.Preflop},
+	}
+	if got := h.GetActions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetActions() = %+v, want %+v", got, want)
+	}
+	if got := h.GetPot(); got != 4 {
+		t.Errorf("GetPot() = %v, want 4", got)
+	}
+}
+
+func TestNewHandErrors(t *testing.T) {
+	tests := []game.Config{
+		{Players: []game.Player{{"A", 100}}, SmallBlind: 1, BigBlind: 2},
+		{Players: []game.Player{{"A", 100}, {"B", 100}}, Button: 2, SmallBlind: 1, BigBlind: 2},
+		{Players: []game.Player{{"A", 100}, {"B", 100}}, SmallBlind: 2, BigBlind: 1},
+		{Players: []game.Player{{"A", 100}, {"B", 0}}, SmallBlind: 1, BigBlind: 2},
+		// Too many players for a full deck, or for the 36 cards of a short deck
+		{Players: players(23, 100), SmallBlind: 1, BigBlind: 2},
+		{Players: players(15, 100), SmallBlind: 1, BigBlind: 2, Deck: base.NewShortDeck()},
+	}
+	for _, config := range tests {
+		if _, err := game.NewHand(config); err == nil {
+			t.Errorf("NewHand(%+v) succeeded, want error", config)
+		}
+	}
+}
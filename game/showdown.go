@@ -0,0 +1,160 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// OddChipRule is a house rule deciding which of the players splitting a pot receive the chips that cannot be split
+// evenly between them, one chip each.
+type OddChipRule int
+
+const (
+	// OddChipLeftOfButton gives odd chips to the splitting players closest to the left of the button.
+	OddChipLeftOfButton OddChipRule = iota
+	// OddChipHighestCard gives odd chips to the splitting players holding the highest card in their hole, where ranks
+	// are compared first and then suits, from clubs up to spades.
+	OddChipHighestCard
+)
+
+// Contender is a player's claim on the pot at the end of a hand.
+type Contender struct {
+	// Hole is ignored if the player folded.
+	Hole []base.Card
+	// Contributed is the number of chips that the player has put into the pot over the whole hand.
+	Contributed int64
+	// Folded is whether the player has folded, in which case they cannot win any pot.
+	Folded bool
+}
+
+// Pot is the main pot or a side pot, which can only be won by the players who contributed to all of it.
+type Pot struct {
+	Amount   int64 `json:"amount"`
+	Eligible []int `json:"eligible"`
+	Winners  []int `json:"winners"`
+}
+
+// ResolveShowdown splits the chips contributed by the contenders, given in seat order, into a main pot and side pots,
+// awards each pot to the eligible contenders with the best hand according to base.GetScore, splitting it between ties
+// with odd chips given out according to the house rule, and returns the payout of every contender along with the pots
+// from the main pot up. The board must have 5 cards unless every contender but one has folded.
+func ResolveShowdown(contenders []Contender, board []base.Card, button int, oddChips OddChipRule) ([]int64, []Pot,
+	error) {
+	n := len(contenders)
+	if button < 0 || button >= n {
+		return nil, nil, fmt.Errorf("button %v is not a seat", button)
+	}
+
+	// Every distinct contribution of a remaining player caps a pot
+	levels := []int64{}
+	remaining := 0
+	for i, c := range contenders {
+		if c.Contributed < 0 {
+			return nil, nil, fmt.Errorf("seat %v has a negative contribution", i)
+		}
+		if !c.Folded {
+			levels = append(levels, c.Contributed)
+			remaining++
+		}
+	}
+	if remaining == 0 {
+		return nil, nil, fmt.Errorf("every player has folded")
+	}
+	sort.Slice(levels, func(x, y int) bool { return levels[x] < levels[y] })
+	distinct := levels[:1]
+	for _, level := range levels[1:] {
+		if level != distinct[len(distinct)-1] {
+			distinct = append(distinct, level)
+		}
+	}
+	levels = distinct
+
+	scores := make([]base.Score, n)
+	if remaining > 1 {
+		if len(board) != 5 {
+			return nil, nil, fmt.Errorf("a showdown between %v players needs a board of 5 cards", remaining)
+		}
+		for i, c := range contenders {
+			if c.Folded {
+				continue
+			}
+			score, err := base.GetScore(append(append([]base.Card{}, c.Hole...), board...))
+			if err != nil {
+				return nil, nil, fmt.Errorf("seat %v cannot be scored: %v", i, err)
+			}
+			scores[i] = score
+		}
+	}
+
+	payouts := make([]int64, n)
+	pots := []Pot{}
+	previous := int64(0)
+	for k, level := range levels {
+		pot := Pot{Eligible: []int{}, Winners: []int{}}
+		for i, c := range contenders {
+			// Chips contributed beyond what any remaining player can match go into the last pot
+			capped := c.Contributed
+			if capped > level && k < len(levels)-1 {
+				capped = level
+			}
+			if capped > previous {
+				pot.Amount += capped - previous
+			}
+			if !c.Folded && c.Contributed >= level {
+				pot.Eligible = append(pot.Eligible, i)
+			}
+		}
+		previous = level
+		// Remaining players who put nothing in have no pot to themselves, but they still win any chips folded to them
+		if pot.Amount == 0 {
+			continue
+		}
+
+		best := base.Score(0)
+		for _, i := range pot.Eligible {
+			if scores[i] > best || len(pot.Winners) == 0 {
+				best = scores[i]
+				pot.Winners = []int{}
+			}
+			if scores[i] == best {
+				pot.Winners = append(pot.Winners, i)
+			}
+		}
+		for _, i := range pot.Winners {
+			payouts[i] += pot.Amount / int64(len(pot.Winners))
+		}
+		odd := pot.Amount % int64(len(pot.Winners))
+		for _, i := range orderOddChips(contenders, pot.Winners, button, oddChips)[:odd] {
+			payouts[i]++
+		}
+		pots = append(pots, pot)
+	}
+	return payouts, pots, nil
+}
+
+// orderOddChips returns the winners of a pot in the order that they receive odd chips.
+func orderOddChips(contenders []Contender, winners []int, button int, oddChips OddChipRule) []int {
+	n := len(contenders)
+	result := append([]int{}, winners...)
+	switch oddChips {
+	case OddChipHighestCard:
+		highest := func(i int) base.Card {
+			card := base.Card(0)
+			for _, c := range contenders[i].Hole {
+				if c > card {
+					card = c
+				}
+			}
+			return card
+		}
+		sort.SliceStable(result, func(x, y int) bool { return highest(result[x]) > highest(result[y]) })
+	default:
+		distance := func(i int) int {
+			return (i - button - 1 + n) % n
+		}
+		sort.SliceStable(result, func(x, y int) bool { return distance(result[x]) < distance(result[y]) })
+	}
+	return result
+}
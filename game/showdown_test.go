@@ -0,0 +1,133 @@
+package game_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+func TestResolveShowdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		holes       []string
+		contributed []int64
+		folded      []bool
+		board       string
+		button      int
+		oddChips    game.OddChipRule
+		want        []int64
+		pots        int
+	}{
+		{
+			name:        "best hand wins",
+			holes:       []string{"AsAd", "KsKd", "QsQd"},
+			contributed: []int64{100, 100, 100},
+			folded:      []bool{false, false, false},
+			board:       "2c7h9dTc3s",
+			want:        []int64{300, 0, 0},
+			pots:        1,
+		},
+		{
+			name:        "short all-in wins the main pot only",
+			holes:       []string{"AsAd", "KsKd", "QsQd"},
+			contributed: []int64{50, 100, 100},
+			folded:      []bool{false, false, false},
+			board:       "2c7h9dTc3s",
+			want:        []int64{150, 100, 0},
+			pots:        2,
+		},
+		{
+			name:        "folded chips stay in the pot",
+			holes:       []string{"AsAd", "KsKd", "QsQd", "JsJd"},
+			contributed: []int64{30, 100, 60, 80},
+			folded:      []bool{false, false, false, true},
+			board:       "2c7h9dTc3s",
+			want:        []int64{120, 150, 0, 0},
+			pots:        3,
+		},
+		{
+			name:        "split pot",
+			holes:       []string{"AsKd", "AcKh", "QsQd"},
+			contributed: []int64{100, 100, 100},
+			folded:      []bool{false, false, false},
+			board:       "2cQhJdTc3s",
+			want:        []int64{150, 150, 0},
+			pots:        1,
+		},
+		{
+			name:        "odd chip left of the button",
+			holes:       []string{"AsKd", "AcKh", "QsQd"},
+			contributed: []int64{33, 33, 35},
+			folded:      []bool{false, false, true},
+			board:       "2cQhJdTc3s",
+			button:      0,
+			want:        []int64{50, 51, 0},
+			pots:        1,
+		},
+		{
+			name:        "odd chip to the highest card",
+			holes:       []string{"AsKd", "AcKh", "QsQd"},
+			contributed: []int64{33, 33, 35},
+			folded:      []bool{false, false, true},
+			board:       "2cQhJdTc3s",
+			oddChips:    game.OddChipHighestCard,
+			want:        []int64{51, 50, 0},
+			pots:        1,
+		},
+		{
+			name:        "everyone else folded",
+			holes:       []string{"2s7d", "AcKh"},
+			contributed: []int64{10, 4},
+			folded:      []bool{false, true},
+			want:        []int64{14, 0},
+			pots:        1,
+		},
+		{
+			name:        "folded to a player who put nothing in",
+			holes:       []string{"AcKh", "2s7d"},
+			contributed: []int64{10, 0},
+			folded:      []bool{true, false},
+			want:        []int64{0, 10},
+			pots:        1,
+		},
+		{
+			name:        "folded chips go to the next pot",
+			holes:       []string{"AcKh", "2s7d", "QsQd"},
+			contributed: []int64{10, 0, 5},
+			folded:      []bool{true, false, false},
+			board:       "2cQhJdTc3s",
+			want:        []int64{0, 0, 15},
+			pots:        1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contenders := make([]game.Contender, len(tt.holes))
+			for i, hole := range tt.holes {
+				cards, _ := base.ParseCards(hole)
+				contenders[i] = game.Contender{Hole: cards, Contributed: tt.contributed[i], Folded: tt.folded[i]}
+			}
+			board, _ := base.ParseCards(tt.board)
+			got, pots, err := game.ResolveShowdown(contenders, board, tt.button, tt.oddChips)
+			if err != nil {
+				t.Fatalf("ResolveShowdown() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveShowdown() = %v, want %v", got, tt.want)
+			}
+			if len(pots) != tt.pots {
+				t.Errorf("ResolveShowdown() has %v pots, want %v", len(pots), tt.pots)
+			}
+			total, paid := int64(0), int64(0)
+			for i := range got {
+				total += tt.contributed[i]
+				paid += got[i]
+			}
+			if total != paid {
+				t.Errorf("ResolveShowdown() paid %v, want %v", paid, total)
+			}
+		})
+	}
+}
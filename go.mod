@@ -0,0 +1,3 @@
+module github.com/shishichen/strategic-parrot
+
+go 1.16
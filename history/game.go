@@ -0,0 +1,51 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+// FromGame returns the history of a hand played with the game package, which must be over. Seats are numbered from
+// 1 in seat order, every hole is known, and the players who reached a showdown are recorded as showing their holes.
+func FromGame(h *game.Hand) (Hand, error) {
+	if !h.IsOver() {
+		return Hand{}, fmt.Errorf("hand is not over")
+	}
+	config := h.GetConfig()
+	seats := h.GetSeats()
+	payouts := h.GetPayouts()
+	result := Hand{
+		Game:       "Hold'em No Limit",
+		SmallBlind: config.SmallBlind,
+		BigBlind:   config.BigBlind,
+		Ante:       config.Ante,
+		Button:     config.Button,
+		Seats:      make([]Seat, len(seats)),
+		Actions:    h.GetActions(),
+		Board:      h.GetBoard(),
+	}
+	showdown := h.GetStreet() == game.Showdown
+	for i, s := range seats {
+		result.Seats[i] = Seat{
+			Number: i + 1,
+			Name:   s.Name,
+			Stack:  config.Players[i].Stack,
+			Hole:   s.Hole,
+			Showed: showdown && !s.Folded,
+			Won:    payouts[i],
+		}
+		result.Pot += s.Contributed
+	}
+
+	// The hand returns uncalled bets without recording them, so they are whatever the actions put in beyond what
+	// each seat contributed
+	contributed, err := result.GetContributions()
+	if err != nil {
+		return Hand{}, err
+	}
+	for i, s := range seats {
+		result.Seats[i].Returned = contributed[i] - s.Contributed
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+// Package history reads and writes the histories of hands that have been played, e.g. at online poker sites.
+package history
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+// Hand is the history of a single hand: who played it, what they did, which cards were seen, and what they won.
+type Hand struct {
+	// ID is the identifier of the hand given by the site, e.g. the hand number.
+	ID string
+	// Game is the description of the game given by the site, e.g. "Hold'em No Limit".
+	Game string
+	// Table is the name of the table.
+	Table string
+	// MaxSeats is the number of seats at the table, or 0 if unknown.
+	MaxSeats int
+	// Time is the time that the hand started, as written by the site.
+	Time string
+	// Currency is the currency of a cash game, e.g. "USD", in which case amounts are in cents. It is empty if amounts
+	// are in chips.
+	Currency   string
+	SmallBlind int64
+	BigBlind   int64
+	// Ante is the largest ante posted, or 0 if there were no antes.
+	Ante int64
+	// Button is the index into Seats of the player on the button, or -1 if the button was on an empty seat.
+	Button int
+	// Seats are the players dealt into the hand, in the order that they sat around the table.
+	Seats []Seat
+	// Actions are the actions taken in the hand, in order, where each action's seat is an index into Seats. Amounts
+	// follow game.Action, i.e. bets and raises are to a total for the street, and calls and posts are the chips put
+	// in. A dead small blind posted along with the big blind is recorded as an ante, since it doesn't count towards
+	// any bet.
+	Actions []game.Action
+	Board   []base.Card
+	// Pot is the total pot, before the rake.
+	Pot  int64
+	Rake int64
+}
+
+// Seat is a player dealt into a hand.
+type Seat struct {
+	// Number is the number of the seat at the table, as given by the site.
+	Number int
+	Name   string
+	// Stack is the number of chips the player had at the start of the hand.
+	Stack int64
+	// Hole is the hole dealt to the player, if it was seen.
+	Hole []base.Card
+	// Showed is whether the player showed their hole at the end of the hand.
+	Showed bool
+	// Returned is the part of the player's bet that nobody called, which was returned to them.
+	Returned int64
+	// Won is the number of chips that the player collected from the pot.
+	Won int64
+}
+
+// GetContributions returns the number of chips that each seat put into the pot over the whole hand, not counting
+// any bet that was returned.
+func (h *Hand) GetContributions() ([]int64, error) {
+	contributed := make([]int64, len(h.Seats))
+	bets := make([]int64, len(h.Seats))
+	street := game.Street(0)
+	for _, a := range h.Actions {
+		if a.Seat < 0 || a.Seat >= len(h.Seats) {
+			return nil, fmt.Errorf("action %v is taken by seat %v, which is not in the hand", a.Type, a.Seat)
+		}
+		if a.Street != street {
+			street = a.Street
+			bets = make([]int64, len(h.Seats))
+		}
+		switch a.Type {
+		case game.PostAnte:
+			contributed[a.Seat] += a.Amount
+		case game.PostSmallBlind, game.PostBigBlind, game.Call:
+			bets[a.Seat] += a.Amount
+			contributed[a.Seat] += a.Amount
+		case game.Bet, game.Raise:
+			if a.Amount < bets[a.Seat] {
+				return nil, fmt.Errorf("seat %v cannot %v to %v after putting in %v", a.Seat, a.Type, a.Amount,
+					bets[a.Seat])
+			}
+			contributed[a.Seat] += a.Amount - bets[a.Seat]
+			bets[a.Seat] = a.Amount
+		}
+	}
+	for i, s := range h.Seats {
+		contributed[i] -= s.Returned
+	}
+	return contributed, nil
+}
+
+// Check returns an error if the hand is inconsistent, e.g. if players put more chips into the pot than they had, or
+// the pot doesn't add up to what was contributed or to what was won.
+func (h *Hand) Check() error {
+	if len(h.Seats) < 2 {
+		return fmt.Errorf("a hand needs at least 2 players")
+	}
+	if h.Button < -1 || h.Button >= len(h.Seats) {
+		return fmt.Errorf("button %v is not a seat", h.Button)
+	}
+	if n := len(h.Board); n != 0 && n != 3 && n != 4 && n != 5 {
+		return fmt.Errorf("board cannot have %v cards", n)
+	}
+	cards := append([]base.Card{}, h.Board...)
+	for _, s := range h.Seats {
+		cards = append(cards, s.Hole...)
+	}
+	seen := make(map[base.Card]bool)
+	for _, c := range cards {
+		if seen[c] {
+			return fmt.Errorf("card %v is dealt more than once", c)
+		}
+		seen[c] = true
+	}
+
+	contributed, err := h.GetContributions()
+	if err != nil {
+		return err
+	}
+	total, won := int64(0), int64(0)
+	for i, s := range h.Seats {
+		if contributed[i] < 0 || contributed[i] > s.Stack {
+			return fmt.Errorf("%q contributed %v to the pot with a stack of %v", s.Name, contributed[i], s.Stack)
+		}
+		total += contributed[i]
+		won += s.Won
+	}
+	if total != h.Pot {
+		return fmt.Errorf("players contributed %v, but the pot is %v", total, h.Pot)
+	}
+	if won+h.Rake != h.Pot {
+		return fmt.Errorf("players won %v with a rake of %v, but the pot is %v", won, h.Rake, h.Pot)
+	}
+	return nil
+}
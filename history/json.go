@@ -0,0 +1,299 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+// JSONVersion is the version of the JSON interchange format, which is modeled on the Open Hand History standard, with
+// one object per hand:
+//
+//	{
+//	  "spec_version": "1.0",
+//	  "game_number": "100000001",
+//	  "game_type": "Hold'em No Limit",
+//	  "table_name": "Alpha",
+//	  "table_size": 6,
+//	  "start_date": "2023/01/15 20:30:00 ET",
+//	  "currency": "USD",
+//	  "small_blind_amount": 1,
+//	  "big_blind_amount": 2,
+//	  "ante_amount": 0,
+//	  "dealer_seat": 1,
+//	  "players": [
+//	    {"id": 0, "seat": 1, "name": "Alice", "starting_stack": 200, "cards": ["Ah", "Kd"], "showed": false,
+//	      "returned": 20, "winnings": 28},
+//	    ...
+//	  ],
+//	  "rounds": [
+//	    {"street": "Preflop", "cards": [], "actions": [
+//	      {"player_id": 1, "action": "Post SB", "amount": 1, "is_allin": false},
+//	      ...
+//	    ]},
+//	    {"street": "Flop", "cards": ["2c", "7d", "Kh"], "actions": [...]},
+//	    ...
+//	  ],
+//	  "total_pot": 29,
+//	  "rake": 1
+//	}
+//
+// Amounts are integers, in cents if there is a currency and in chips otherwise. Players are listed in the order that
+// they sat around the table, and actions refer to them by id, which is their index in that list. The dealer seat is
+// the seat number of the button, or 0 if the button was on an empty seat. Cards are written as a rank followed by a
+// suit, and a player's cards are left out if they weren't seen. Rounds are listed in order, each with the cards dealt
+// to the board on that street and the actions taken on it, and the board is every round's cards put together. Actions
+// are one of "Post Ante", "Post SB", "Post BB", "Fold", "Check", "Call", "Bet", or "Raise", with amounts as in Hand.
+const JSONVersion = "1.0"
+
+type jsonHand struct {
+	SpecVersion string       `json:"spec_version"`
+	GameNumber  string       `json:"game_number"`
+	GameType    string       `json:"game_type"`
+	TableName   string       `json:"table_name"`
+	TableSize   int          `json:"table_size"`
+	StartDate   string       `json:"start_date"`
+	Currency    string       `json:"currency"`
+	SmallBlind  int64        `json:"small_blind_amount"`
+	BigBlind    int64        `json:"big_blind_amount"`
+	Ante        int64        `json:"ante_amount"`
+	DealerSeat  int          `json:"dealer_seat"`
+	Players     []jsonPlayer `json:"players"`
+	Rounds      []jsonRound  `json:"rounds"`
+	TotalPot    int64        `json:"total_pot"`
+	Rake        int64        `json:"rake"`
+}
+
+type jsonPlayer struct {
+	ID            int      `json:"id"`
+	Seat          int      `json:"seat"`
+	Name          string   `json:"name"`
+	StartingStack int64    `json:"starting_stack"`
+	Cards         []string `json:"cards,omitempty"`
+	Showed        bool     `json:"showed"`
+	Returned      int64    `json:"returned"`
+	Winnings      int64    `json:"winnings"`
+}
+
+type jsonRound struct {
+	Street  string       `json:"street"`
+	Cards   []string     `json:"cards"`
+	Actions []jsonAction `json:"actions"`
+}
+
+type jsonAction struct {
+	PlayerID int    `json:"player_id"`
+	Action   string `json:"action"`
+	Amount   int64  `json:"amount"`
+	IsAllIn  bool   `json:"is_allin"`
+}
+
+// actionNames are the names of actions in the JSON interchange format.
+var actionNames = map[game.ActionType]string{
+	game.PostAnte:       "Post Ante",
+	game.PostSmallBlind: "Post SB",
+	game.PostBigBlind:   "Post BB",
+	game.Fold:           "Fold",
+	game.Check:          "Check",
+	game.Call:           "Call",
+	game.Bet:            "Bet",
+	game.Raise:          "Raise",
+}
+
+// streets are the streets that rounds can be on, in order.
+var streets = []game.Street{game.Preflop, game.Flop, game.Turn, game.River, game.Showdown}
+
+// WriteJSON writes the hands in the JSON interchange format, one object per line.
+func WriteJSON(w io.Writer, hands []Hand) error {
+	encoder := json.NewEncoder(w)
+	for _, h := range hands {
+		if err := encoder.Encode(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadJSON reads every hand written in the JSON interchange format, whether one object per line as by WriteJSON or
+// otherwise separated by whitespace.
+func ReadJSON(r io.Reader) ([]Hand, error) {
+	hands := []Hand{}
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var h Hand
+		if err := decoder.Decode(&h); err != nil {
+			return nil, fmt.Errorf("hand %v: %v", len(hands)+1, err)
+		}
+		hands = append(hands, h)
+	}
+	return hands, nil
+}
+
+// MarshalJSON writes the hand in the JSON interchange format.
+func (h Hand) MarshalJSON() ([]byte, error) {
+	j := jsonHand{
+		SpecVersion: JSONVersion,
+		GameNumber:  h.ID,
+		GameType:    h.Game,
+		TableName:   h.Table,
+		TableSize:   h.MaxSeats,
+		StartDate:   h.Time,
+		Currency:    h.Currency,
+		SmallBlind:  h.SmallBlind,
+		BigBlind:    h.BigBlind,
+		Ante:        h.Ante,
+		Players:     make([]jsonPlayer, len(h.Seats)),
+		Rounds:      []jsonRound{},
+		TotalPot:    h.Pot,
+		Rake:        h.Rake,
+	}
+	if h.Button >= 0 && h.Button < len(h.Seats) {
+		j.DealerSeat = h.Seats[h.Button].Number
+	}
+	for i, s := range h.Seats {
+		j.Players[i] = jsonPlayer{i, s.Number, s.Name, s.Stack, formatCards(s.Hole), s.Showed, s.Returned, s.Won}
+	}
+
+	// Actions are grouped into rounds by street, which only keeps them in order if their streets are in order
+	for i := 1; i < len(h.Actions); i++ {
+		if h.Actions[i].Street < h.Actions[i-1].Street {
+			return nil, fmt.Errorf("action %v on the %v comes after the %v", i, h.Actions[i].Street,
+				h.Actions[i-1].Street)
+		}
+	}
+
+	// Each street's round deals the cards for that street, and the flop deals 3 of them
+	dealt := 0
+	for _, street := range streets {
+		round := jsonRound{Street: street.String(), Cards: []string{}, Actions: []jsonAction{}}
+		if street != game.Preflop && street != game.Showdown && dealt < len(h.Board) {
+			size := 1
+			if street == game.Flop {
+				size = 3
+			}
+			if dealt+size > len(h.Board) {
+				return nil, fmt.Errorf("board %v isn't dealt by street", h.Board)
+			}
+			round.Cards = formatCards(h.Board[dealt : dealt+size])
+			dealt += size
+		}
+		for _, a := range h.Actions {
+			if a.Street == street {
+				name, ok := actionNames[a.Type]
+				if !ok {
+					return nil, fmt.Errorf("action %v cannot be written", a.Type)
+				}
+				round.Actions = append(round.Actions, jsonAction{a.Seat, name, a.Amount, a.AllIn})
+			}
+		}
+		if len(round.Cards) > 0 || len(round.Actions) > 0 {
+			j.Rounds = append(j.Rounds, round)
+		}
+	}
+	if dealt != len(h.Board) {
+		return nil, fmt.Errorf("board %v isn't dealt by street", h.Board)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads the hand from the JSON interchange format.
+func (h *Hand) UnmarshalJSON(data []byte) error {
+	var j jsonHand
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.SpecVersion != JSONVersion {
+		return fmt.Errorf("spec version %q is not supported, want %q", j.SpecVersion, JSONVersion)
+	}
+
+	result := Hand{
+		ID:         j.GameNumber,
+		Game:       j.GameType,
+		Table:      j.TableName,
+		MaxSeats:   j.TableSize,
+		Time:       j.StartDate,
+		Currency:   j.Currency,
+		SmallBlind: j.SmallBlind,
+		BigBlind:   j.BigBlind,
+		Ante:       j.Ante,
+		Button:     -1,
+		Seats:      make([]Seat, len(j.Players)),
+		Actions:    []game.Action{},
+		Board:      []base.Card{},
+		Pot:        j.TotalPot,
+		Rake:       j.Rake,
+	}
+	for i, p := range j.Players {
+		if p.ID != i {
+			return fmt.Errorf("player %q has id %v, want %v", p.Name, p.ID, i)
+		}
+		hole, err := parseCards(p.Cards)
+		if err != nil {
+			return err
+		}
+		if len(hole) == 0 {
+			hole = nil
+		}
+		result.Seats[i] = Seat{p.Seat, p.Name, p.StartingStack, hole, p.Showed, p.Returned, p.Winnings}
+		if p.Seat == j.DealerSeat {
+			result.Button = i
+		}
+	}
+
+	previous := game.Street(0)
+	for _, r := range j.Rounds {
+		street := game.Street(0)
+		for _, s := range streets {
+			if s.String() == r.Street {
+				street = s
+			}
+		}
+		if street == 0 {
+			return fmt.Errorf("round %q is not a street", r.Street)
+		}
+		if street <= previous {
+			return fmt.Errorf("round %q comes after %v", r.Street, previous)
+		}
+		previous = street
+		cards, err := parseCards(r.Cards)
+		if err != nil {
+			return err
+		}
+		result.Board = append(result.Board, cards...)
+		for _, a := range r.Actions {
+			if a.PlayerID < 0 || a.PlayerID >= len(result.Seats) {
+				return fmt.Errorf("action %q is taken by player %v, who is not in the hand", a.Action, a.PlayerID)
+			}
+			actionType := game.ActionType(0)
+			for t, name := range actionNames {
+				if name == a.Action {
+					actionType = t
+				}
+			}
+			if actionType == 0 {
+				return fmt.Errorf("action %q is not supported", a.Action)
+			}
+			result.Actions = append(result.Actions, game.Action{Seat: a.PlayerID, Type: actionType, Amount: a.Amount,
+				Street: street, AllIn: a.IsAllIn})
+		}
+	}
+
+	*h = result
+	return nil
+}
+
+func formatCards(cards []base.Card) []string {
+	result := make([]string, len(cards))
+	for i, c := range cards {
+		result[i] = c.String()
+	}
+	return result
+}
+
+func parseCards(cards []string) ([]base.Card, error) {
+	return base.ParseCards(strings.Join(cards, " "))
+}
@@ -0,0 +1,112 @@
+package history_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+	"github.com/shishichen/strategic-parrot/history"
+)
+
+// playHand plays a hand dealt from an unshuffled deck, where the first two players go all-in preflop.
+func playHand(t *testing.T) *game.Hand {
+	t.Helper()
+	players := []game.Player{{Name: "A", Stack: 50}, {Name: "B", Stack: 100}, {Name: "C", Stack: 100}}
+	h, err := game.NewHand(game.Config{Players: players, Button: 0, SmallBlind: 1, BigBlind: 2, Ante: 1,
+		Deck: base.NewDeck()})
+	if err != nil {
+		t.Fatalf("NewHand() error = %v", err)
+	}
+	for _, a := range []game.Action{{Seat: 0, Type: game.Raise, Amount: 49}, {Seat: 1, Type: game.Raise, Amount: 99},
+		{Seat: 2, Type: game.Fold}} {
+		if err := h.Act(a); err != nil {
+			t.Fatalf("Act(%+v) error = %v", a, err)
+		}
+	}
+	return h
+}
+
+func TestFromGame(t *testing.T) {
+	h, err := history.FromGame(playHand(t))
+	if err != nil {
+		t.Fatalf("FromGame() error = %v", err)
+	}
+	if err := h.Check(); err != nil {
+		t.Errorf("Check() error = %v", err)
+	}
+	if h.Pot != 103 || len(h.Board) != 5 || h.Button != 0 {
+		t.Errorf("FromGame() = %+v", h)
+	}
+	// The part of seat 1's raise that seat 0 couldn't call is returned
+	want := []history.Seat{
+		{Number: 1, Name: "A", Stack: 50, Hole: h.Seats[0].Hole, Showed: true, Won: 103},
+		{Number: 2, Name: "B", Stack: 100, Hole: h.Seats[1].Hole, Showed: true, Returned: 50},
+		{Number: 3, Name: "C", Stack: 100, Hole: h.Seats[2].Hole},
+	}
+	if !reflect.DeepEqual(h.Seats, want) {
+		t.Errorf("FromGame() seats = %+v, want %+v", h.Seats, want)
+	}
+
+	if _, err := history.FromGame(newUnfinishedHand(t)); err == nil {
+		t.Errorf("FromGame() of an unfinished hand succeeded, want error")
+	}
+}
+
+func newUnfinishedHand(t *testing.T) *game.Hand {
+	t.Helper()
+	h, err := game.NewHand(game.Config{Players: []game.Player{{Name: "A", Stack: 10}, {Name: "B", Stack: 10}},
+		SmallBlind: 1, BigBlind: 2})
+	if err != nil {
+		t.Fatalf("NewHand() error = %v", err)
+	}
+	return h
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	hands, _ := parseFile(t)
+	played, err := history.FromGame(playHand(t))
+	if err != nil {
+		t.Fatalf("FromGame() error = %v", err)
+	}
+	hands = append(hands, played)
+
+	var buffer bytes.Buffer
+	if err := history.WriteJSON(&buffer, hands); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if lines := strings.Count(buffer.String(), "\n"); lines != len(hands) {
+		t.Errorf("WriteJSON() wrote %v lines, want %v", lines, len(hands))
+	}
+	got, err := history.ReadJSON(&buffer)
+	if err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, hands) {
+		t.Errorf("ReadJSON() = %+v, want %+v", got, hands)
+	}
+}
+
+func TestReadJSONErrors(t *testing.T) {
+	valid := `{"spec_version": "1.0", "dealer_seat": 1, "players": [{"id": 0, "seat": 1, "name": "A"}], ` +
+		`"rounds": [{"street": "Preflop", "cards": [], "actions": [{"player_id": 0, "action": "Fold"}]}]}`
+	if _, err := history.ReadJSON(strings.NewReader(valid)); err != nil {
+		t.Fatalf("ReadJSON(%q) error = %v", valid, err)
+	}
+	tests := []string{
+		strings.Replace(valid, `"1.0"`, `"2.0"`, 1),
+		strings.Replace(valid, `"id": 0`, `"id": 1`, 1),
+		strings.Replace(valid, `"Fold"`, `"Muck"`, 1),
+		strings.Replace(valid, `"player_id": 0`, `"player_id": 1`, 1),
+		strings.Replace(valid, `"Preflop"`, `"Fourth"`, 1),
+		strings.Replace(valid, `"cards": []`, `"cards": ["Xx"]`, 1),
+		valid + " {",
+	}
+	for _, test := range tests {
+		if _, err := history.ReadJSON(strings.NewReader(test)); err == nil {
+			t.Errorf("ReadJSON(%q) succeeded, want error", test)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+package history
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+)
+
+// ParseError is an error in a single hand of a hand history.
+type ParseError struct {
+	// ID is the identifier of the hand, if it could be read.
+	ID string
+	// Line is the line of the history where the error was found, counting from 1.
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("hand at line %v: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("hand #%v at line %v: %v", e.ID, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	startPattern     = regexp.MustCompile(`^PokerStars .*Hand #`)
+	tablePattern     = regexp.MustCompile(`^Table '(.*)' (?:(\d+)-max )?(?:\(Play Money\) )?Seat #(\d+) is the button$`)
+	seatPattern      = regexp.MustCompile(`^Seat (\d+): (.+) \((\S+) in chips(?:, .*)?\)(?: .*)?$`)
+	streetPattern    = regexp.MustCompile(`^\*\*\* (.+?) \*\*\*(.*)$`)
+	dealtPattern     = regexp.MustCompile(`^Dealt to (.+) \[([^\[\]]*)\]$`)
+	uncalledPattern  = regexp.MustCompile(`^Uncalled bet \((\S+)\) returned to (.+)$`)
+	collectedPattern = regexp.MustCompile(`^(.+) collected (\S+) from (?:(?:main|side) )?pot(?:-\d+)?$`)
+	totalPattern     = regexp.MustCompile(`^Total pot (\S+)(?: .*)? \| Rake (\S+)`)
+	boardPattern     = regexp.MustCompile(`^Board \[([^\[\]]*)\]$`)
+	shownPattern     = regexp.MustCompile(`^Seat (\d+): .* (?:showed|mucked) \[([^\[\]]*)\]`)
+	cardsPattern     = regexp.MustCompile(`\[([^\[\]]*)\]`)
+)
+
+// headerPattern matches the first line of a hand, e.g. "PokerStars Hand #123:  Hold'em No Limit ($0.01/$0.02 USD) -
+// 2023/01/15 20:30:00 ET", capturing the ID, game, blinds, currency, and time.
+var headerPattern = regexp.MustCompile(`^PokerStars (?:.+ )?Hand #(\d+): +(.+?) ` +
+	`\(([^()/\s]+)/([^()/\s]+)(?: ([A-Z]{3}))?\) - (.+)$`)
+
+// currencies are the currencies of the symbols that PokerStars writes before amounts.
+var currencies = map[string]string{"$": "USD", "€": "EUR", "£": "GBP"}
+
+// ParsePokerStars reads every hand in a PokerStars hand history, where hands start with a line like "PokerStars Hand
+// #123: ..." and are usually separated by blank lines. A hand that cannot be read doesn't stop the others from being
+// read, and instead results in a *ParseError in the returned errors. The final error is only returned if the history
+// itself cannot be read.
+func ParsePokerStars(r io.Reader) ([]Hand, []error, error) {
+	hands := []Hand{}
+	errs := []error{}
+	lines := []string{}
+	start := 0
+	flush := func() {
+		if len(lines) == 0 {
+			return
+		}
+		p := &parser{start: start}
+		if err := p.parse(lines); err != nil {
+			errs = append(errs, err)
+		} else {
+			hands = append(hands, p.hand)
+		}
+		lines = []string{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if startPattern.MatchString(line) {
+			flush()
+			start = n
+		}
+		if len(lines) == 0 && !startPattern.MatchString(line) {
+			if line != "" {
+				errs = append(errs, &ParseError{Line: n, Err: fmt.Errorf("%q is not part of a hand", line)})
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return hands, errs, scanner.Err()
+}
+
+// parser reads a single hand.
+type parser struct {
+	hand Hand
+	// start is the line of the history that the hand starts on.
+	start int
+	// line is the index of the line being read.
+	line   int
+	street game.Street
+	// summary is whether the summary at the end of the hand is being read.
+	summary bool
+	// button is the number of the seat with the button.
+	button int
+	// total is whether the total pot has been read.
+	total bool
+}
+
+// parse reads the lines of the hand into the hand, returning a *ParseError if it cannot.
+func (p *parser) parse(lines []string) error {
+	p.hand = Hand{Button: -1, Seats: []Seat{}, Actions: []game.Action{}, Board: []base.Card{}}
+	p.street = game.Preflop
+	for p.line = 0; p.line < len(lines); p.line++ {
+		if lines[p.line] == "" {
+			continue
+		}
+		var err error
+		if p.line == 0 {
+			err = p.parseHeader(lines[0])
+		} else {
+			err = p.parseLine(lines[p.line])
+		}
+		if err != nil {
+			return &ParseError{ID: p.hand.ID, Line: p.start + p.line, Err: err}
+		}
+	}
+
+	err := p.finish()
+	if err == nil {
+		err = p.hand.Check()
+	}
+	if err != nil {
+		return &ParseError{ID: p.hand.ID, Line: p.start, Err: err}
+	}
+	return nil
+}
+
+func (p *parser) parseHeader(line string) error {
+	m := headerPattern.FindStringSubmatch(line)
+	if m == nil {
+		return fmt.Errorf("invalid header %q", line)
+	}
+	p.hand.ID, p.hand.Game, p.hand.Currency, p.hand.Time = m[1], m[2], m[5], m[6]
+	if p.hand.Currency == "" {
+		for symbol, currency := range currencies {
+			if strings.HasPrefix(m[3], symbol) {
+				p.hand.Currency = currency
+			}
+		}
+	}
+	var err error
+	if p.hand.SmallBlind, err = p.parseAmount(m[3]); err != nil {
+		return err
+	}
+	p.hand.BigBlind, err = p.parseAmount(m[4])
+	return err
+}
+
+func (p *parser) parseLine(line string) error {
+	if m := streetPattern.FindStringSubmatch(line); m != nil {
+		return p.parseStreet(m[1], m[2])
+	}
+	if p.summary {
+		return p.parseSummary(line)
+	}
+
+	if m := tablePattern.FindStringSubmatch(line); m != nil {
+		p.hand.Table = m[1]
+		p.hand.MaxSeats, _ = strconv.Atoi(m[2])
+		p.button, _ = strconv.Atoi(m[3])
+		return nil
+	}
+	if m := seatPattern.FindStringSubmatch(line); m != nil && len(p.hand.Actions) == 0 {
+		// Players sitting out aren't dealt into the hand
+		if strings.HasSuffix(line, " is sitting out") || strings.Contains(line, " out of hand") {
+			return nil
+		}
+		number, _ := strconv.Atoi(m[1])
+		stack, err := p.parseAmount(m[3])
+		if err != nil {
+			return err
+		}
+		if p.findSeat(number) >= 0 {
+			return fmt.Errorf("seat %v is listed more than once", number)
+		}
+		p.hand.Seats = append(p.hand.Seats, Seat{Number: number, Name: m[2], Stack: stack})
+		return nil
+	}
+	if m := dealtPattern.FindStringSubmatch(line); m != nil {
+		seat := p.findPlayer(m[1])
+		if seat < 0 {
+			return fmt.Errorf("cards are dealt to %q, who is not seated", m[1])
+		}
+		return p.parseHole(seat, m[2])
+	}
+	if m := uncalledPattern.FindStringSubmatch(line); m != nil {
+		seat := p.findPlayer(m[2])
+		if seat < 0 {
+			return fmt.Errorf("a bet is returned to %q, who is not seated", m[2])
+		}
+		amount, err := p.parseAmount(m[1])
+		p.hand.Seats[seat].Returned += amount
+		return err
+	}
+	if m := collectedPattern.FindStringSubmatch(line); m != nil {
+		if seat := p.findPlayer(m[1]); seat >= 0 {
+			amount, err := p.parseAmount(m[2])
+			p.hand.Seats[seat].Won += amount
+			return err
+		}
+	}
+	if seat, verb := p.findAction(line); seat >= 0 {
+		return p.parseAction(seat, verb)
+	}
+	// Anything else, e.g. chat or players joining the table, doesn't affect the hand
+	return nil
+}
+
+// parseStreet reads a line like "*** FLOP *** [2c 7d Kh]" that starts a new part of the hand.
+func (p *parser) parseStreet(name string, rest string) error {
+	groups := cardsPattern.FindAllStringSubmatch(rest, -1)
+	streets := map[string]game.Street{"FLOP": game.Flop, "TURN": game.Turn, "RIVER": game.River}
+	switch name {
+	case "HOLE CARDS":
+		p.street = game.Preflop
+	case "FLOP", "TURN", "RIVER":
+		if streets[name] != p.street+1 {
+			return fmt.Errorf("%v is dealt after %v", strings.ToLower(name), strings.ToLower(p.street.String()))
+		}
+		if len(groups) == 0 {
+			return fmt.Errorf("%v has no cards", strings.ToLower(name))
+		}
+		cards, err := base.ParseCards(groups[len(groups)-1][1])
+		if err != nil {
+			return err
+		}
+		want := 1
+		if name == "FLOP" {
+			want = 3
+		}
+		if len(cards) != want {
+			return fmt.Errorf("%v has %v cards, want %v", strings.ToLower(name), len(cards), want)
+		}
+		p.hand.Board = append(p.hand.Board, cards...)
+		p.street = streets[name]
+	case "SHOW DOWN":
+		p.street = game.Showdown
+	case "SUMMARY":
+		p.summary = true
+	default:
+		return fmt.Errorf("%q is not supported", name)
+	}
+	return nil
+}
+
+// parseAction reads what a player did from the rest of a line like "Alice: raises $2 to $4".
+func (p *parser) parseAction(seat int, verb string) error {
+	allIn := strings.HasSuffix(verb, " and is all-in")
+	verb = strings.TrimSuffix(verb, " and is all-in")
+	fields := strings.Fields(verb)
+	if len(fields) == 0 {
+		return nil
+	}
+	action := game.Action{Seat: seat, Street: p.street, AllIn: allIn}
+	amount := func(i int) error {
+		if i >= len(fields) {
+			return fmt.Errorf("%q is missing an amount", verb)
+		}
+		var err error
+		action.Amount, err = p.parseAmount(fields[i])
+		return err
+	}
+
+	var err error
+	switch {
+	case fields[0] == "folds":
+		action.Type = game.Fold
+		if m := cardsPattern.FindStringSubmatch(verb); m != nil {
+			err = p.parseHole(seat, m[1])
+		}
+	case fields[0] == "checks":
+		action.Type = game.Check
+	case fields[0] == "calls":
+		action.Type, err = game.Call, amount(1)
+	case fields[0] == "bets":
+		action.Type, err = game.Bet, amount(1)
+	case fields[0] == "raises":
+		if len(fields) < 4 || fields[2] != "to" {
+			return fmt.Errorf("invalid raise %q", verb)
+		}
+		action.Type, err = game.Raise, amount(3)
+	case strings.HasPrefix(verb, "posts the ante "):
+		action.Type, err = game.PostAnte, amount(3)
+		if err == nil && action.Amount > p.hand.Ante {
+			p.hand.Ante = action.Amount
+		}
+	case strings.HasPrefix(verb, "posts small blind "):
+		action.Type, err = game.PostSmallBlind, amount(3)
+	case strings.HasPrefix(verb, "posts big blind "):
+		action.Type, err = game.PostBigBlind, amount(3)
+	case strings.HasPrefix(verb, "posts small & big blinds "):
+		// The small blind is dead, so it is recorded as an ante before the big blind
+		if err := amount(5); err != nil {
+			return err
+		}
+		if action.Amount <= p.hand.BigBlind {
+			return fmt.Errorf("small and big blinds of %v are less than the big blind", action.Amount)
+		}
+		p.hand.Actions = append(p.hand.Actions, game.Action{Seat: seat, Type: game.PostAnte,
+			Amount: action.Amount - p.hand.BigBlind, Street: p.street})
+		action.Type, action.Amount = game.PostBigBlind, p.hand.BigBlind
+	case fields[0] == "shows":
+		m := cardsPattern.FindStringSubmatch(verb)
+		if m == nil {
+			return fmt.Errorf("%q shows no cards", p.hand.Seats[seat].Name)
+		}
+		p.hand.Seats[seat].Showed = true
+		return p.parseHole(seat, m[1])
+	default:
+		// Mucking, not showing, and other remarks aren't actions
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.hand.Actions = append(p.hand.Actions, action)
+	return nil
+}
+
+// parseSummary reads a line of the summary at the end of the hand.
+func (p *parser) parseSummary(line string) error {
+	if m := totalPattern.FindStringSubmatch(line); m != nil {
+		var err error
+		if p.hand.Pot, err = p.parseAmount(m[1]); err != nil {
+			return err
+		}
+		p.total = true
+		p.hand.Rake, err = p.parseAmount(m[2])
+		return err
+	}
+	if m := boardPattern.FindStringSubmatch(line); m != nil {
+		board, err := base.ParseCards(m[1])
+		if err != nil {
+			return err
+		}
+		if !equalCards(board, p.hand.Board) {
+			return fmt.Errorf("board %v in the summary doesn't match the board %v that was dealt", board,
+				p.hand.Board)
+		}
+		return nil
+	}
+	if m := shownPattern.FindStringSubmatch(line); m != nil {
+		number, _ := strconv.Atoi(m[1])
+		seat := p.findSeat(number)
+		if seat < 0 {
+			return fmt.Errorf("seat %v is not in the hand", number)
+		}
+		return p.parseHole(seat, m[2])
+	}
+	return nil
+}
+
+// finish fills in what can only be known once every line has been read.
+func (p *parser) finish() error {
+	if !p.total {
+		return fmt.Errorf("hand has no total pot")
+	}
+	p.hand.Button = p.findSeat(p.button)
+	return nil
+}
+
+// parseHole reads the cards of a seat's hole, which must be the same as any cards seen before.
+func (p *parser) parseHole(seat int, cards string) error {
+	hole, err := base.ParseCards(cards)
+	if err != nil {
+		return err
+	}
+	s := &p.hand.Seats[seat]
+	if len(s.Hole) > 0 && !equalCards(s.Hole, hole) {
+		return fmt.Errorf("%q shows %v after being dealt %v", s.Name, hole, s.Hole)
+	}
+	s.Hole = hole
+	return nil
+}
+
+// parseAmount reads an amount like "$1.25" or "1500", in cents if the hand has a currency and in chips otherwise.
+func (p *parser) parseAmount(s string) (int64, error) {
+	digits := strings.ReplaceAll(s, ",", "")
+	for symbol := range currencies {
+		digits = strings.TrimPrefix(digits, symbol)
+	}
+	whole, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, fraction = digits[:i], digits[i+1:]
+	}
+	places := 0
+	if p.hand.Currency != "" {
+		places = 2
+	}
+	if len(fraction) > places {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	fraction += strings.Repeat("0", places-len(fraction))
+	amount, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || amount < 0 || whole == "" {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// findSeat returns the index of the seat with the given number, or -1 if it isn't in the hand.
+func (p *parser) findSeat(number int) int {
+	for i, s := range p.hand.Seats {
+		if s.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// findPlayer returns the index of the seat of the player with the given name, or -1 if they aren't in the hand.
+func (p *parser) findPlayer(name string) int {
+	for i, s := range p.hand.Seats {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// findAction returns the index of the seat of the player whose name starts a line like "Alice: folds", along with
+// the rest of the line, or -1 if the line doesn't start with any player's name. Names can contain colons, so the
+// longest matching name is used.
+func (p *parser) findAction(line string) (int, string) {
+	seat := -1
+	for i, s := range p.hand.Seats {
+		if strings.HasPrefix(line, s.Name+": ") && (seat < 0 || len(s.Name) > len(p.hand.Seats[seat].Name)) {
+			seat = i
+		}
+	}
+	if seat < 0 {
+		return -1, ""
+	}
+	return seat, strings.TrimPrefix(line, p.hand.Seats[seat].Name+": ")
+}
+
+func equalCards(a, b []base.Card) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package history_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/game"
+	"github.com/shishichen/strategic-parrot/history"
+)
+
+func cards(t *testing.T, s string) []base.Card {
+	t.Helper()
+	result, err := base.ParseCards(s)
+	if err != nil {
+		t.Fatalf("ParseCards(%q) error = %v", s, err)
+	}
+	return result
+}
+
+func parseFile(t *testing.T) ([]history.Hand, []error) {
+	t.Helper()
+	f, err := os.Open("testdata/pokerstars.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	hands, errs, err := history.ParsePokerStars(f)
+	if err != nil {
+		t.Fatalf("ParsePokerStars() error = %v", err)
+	}
+	return hands, errs
+}
+
+func TestParsePokerStars(t *testing.T) {
+	hands, errs := parseFile(t)
+	if len(hands) != 2 {
+		t.Fatalf("ParsePokerStars() returned %v hands, want 2", len(hands))
+	}
+
+	// A cash game, where amounts are in cents and players sitting out aren't dealt in
+	h := hands[0]
+	if h.ID != "100000001" || h.Game != "Hold'em No Limit" || h.Table != "Alpha" || h.MaxSeats != 6 ||
+		h.Currency != "USD" || h.SmallBlind != 1 || h.BigBlind != 2 || h.Button != 0 || h.Pot != 29 || h.Rake != 1 {
+		t.Errorf("hand 0 = %+v", h)
+	}
+	seats := []history.Seat{
+		{Number: 1, Name: "Alice", Stack: 200, Hole: cards(t, "AhKd"), Returned: 20, Won: 28},
+		{Number: 2, Name: "Bob: the Builder", Stack: 150},
+		{Number: 4, Name: "Carol", Stack: 300},
+	}
+	if !reflect.DeepEqual(h.Seats, seats) {
+		t.Errorf("hand 0 seats = %+v, want %+v", h.Seats, seats)
+	}
+	actions := []game.Action{
+		{Seat: 1, Type: game.PostSmallBlind, Amount: 1, Street: game.Preflop},
+		{Seat: 2, Type: game.PostBigBlind, Amount: 2, Street: game.Preflop},
+		{Seat: 0, Type: game.Raise, Amount: 6, Street: game.Preflop},
+		{Seat: 1, Type: game.Fold, Street: game.Preflop},
+		{Seat: 2, Type: game.Call, Amount: 4, Street: game.Preflop},
+		{Seat: 2, Type: game.Check, Street: game.Flop},
+		{Seat: 0, Type: game.Bet, Amount: 8, Street: game.Flop},
+		{Seat: 2, Type: game.Call, Amount: 8, Street: game.Flop},
+		{Seat: 2, Type: game.Check, Street: game.Turn},
+		{Seat: 0, Type: game.Bet, Amount: 20, Street: game.Turn},
+		{Seat: 2, Type: game.Fold, Street: game.Turn},
+	}
+	if !reflect.DeepEqual(h.Actions, actions) {
+		t.Errorf("hand 0 actions = %+v, want %+v", h.Actions, actions)
+	}
+	if want := cards(t, "2c7dKh9s"); !reflect.DeepEqual(h.Board, want) {
+		t.Errorf("hand 0 board = %v, want %v", h.Board, want)
+	}
+
+	// A tournament with antes and side pots, where every hole is shown
+	h = hands[1]
+	if h.ID != "200000003" || h.Currency != "" || h.SmallBlind != 15 || h.BigBlind != 30 || h.Ante != 5 ||
+		h.Button != 1 || h.Pot != 1300 {
+		t.Errorf("hand 1 = %+v", h)
+	}
+	for i, want := range []string{"QsQh", "AcKc", "JdJh"} {
+		if s := h.Seats[i]; !s.Showed || !reflect.DeepEqual(s.Hole, cards(t, want)) {
+			t.Errorf("hand 1 seat %v = %+v, want %v shown", i, s, want)
+		}
+	}
+	contributed, err := h.GetContributions()
+	if err != nil {
+		t.Fatalf("GetContributions() error = %v", err)
+	}
+	if want := []int64{500, 500, 300}; !reflect.DeepEqual(contributed, want) {
+		t.Errorf("GetContributions() = %v, want %v", contributed, want)
+	}
+	if a := h.Actions[6]; a.Type != game.Raise || a.Amount != 295 || !a.AllIn {
+		t.Errorf("hand 1 action 6 = %+v, want an all-in raise to 295", a)
+	}
+
+	// Bad hands are reported without stopping the rest of the file
+	if len(errs) != 2 {
+		t.Fatalf("ParsePokerStars() returned %v errors, want 2: %v", len(errs), errs)
+	}
+	for i, want := range []history.ParseError{{ID: "100000002", Line: 45}, {ID: "200000004", Line: 83}} {
+		var got *history.ParseError
+		if !errors.As(errs[i], &got) || got.ID != want.ID || got.Line != want.Line {
+			t.Errorf("error %v = %v, want hand #%v at line %v", i, errs[i], want.ID, want.Line)
+		}
+	}
+}
+
+func TestParsePokerStarsErrors(t *testing.T) {
+	tests := []string{
+		"Hello\n",
+		"PokerStars Hand #1: Hold'em No Limit (1/2) - 2023/01/15\nSeat 1: A (100 in chips)\n",
+		"PokerStars Hand #1: Hold'em No Limit ($0.01/$0.02 USD) - 2023/01/15\nSeat 1: A ($1.001 in chips)\n",
+		"PokerStars Hand #1: Hold'em No Limit (1/2) - 2023/01/15\nSeat 1: A (100 in chips)\n" +
+			"Seat 2: B (100 in chips)\nA: posts small blind 1\nB: posts big blind 2\n*** HOLE CARDS ***\n" +
+			"*** TURN *** [2c 7d Kh] [9s]\n*** SUMMARY ***\nTotal pot 3 | Rake 0\n",
+	}
+	for _, test := range tests {
+		hands, errs, err := history.ParsePokerStars(strings.NewReader(test))
+		if err != nil || len(hands) != 0 || len(errs) != 1 {
+			t.Errorf("ParsePokerStars(%q) = %v, %v, %v, want 1 error", test, hands, errs, err)
+		}
+	}
+}
@@ -1,11 +1,113 @@
 package prediction
 
 import (
+	"fmt"
+	"math"
 	"sort"
+	"strconv"
 
 	"github.com/shishichen/strategic-parrot/base"
 )
 
+// Options are the options shared by predictions.
+type Options struct {
+	// Opponents is the number of opponents, each holding a random hole. Zero is treated as a single opponent.
+	// Predictions that are exact against a single opponent sample the joint deal against more, since enumerating every
+	// combination of their holes is infeasible.
+	Opponents int
+	// Dead is a set of cards known not to be in the deck, e.g. folded cards that were exposed.
+	Dead []base.Card
+	// Variant is the variant of poker being played. Nil is treated as Texas Hold'em.
+	Variant base.Variant
+}
+
+func (o Options) getOpponents() int {
+	if o.Opponents <= 0 {
+		return 1
+	}
+	return o.Opponents
+}
+
+func (o Options) getVariant() base.Variant {
+	if o.Variant == nil {
+		return base.TexasHoldem{}
+	}
+	return o.Variant
+}
+
+// checkDead returns an error if the dead cards overlap with the hole or board.
+func (o Options) checkDead(hole, board []base.Card) error {
+	for _, d := range o.Dead {
+		for _, c := range append(append([]base.Card{}, hole...), board...) {
+			if d == c {
+				return fmt.Errorf("dead card %v is already in the hole or board", d)
+			}
+		}
+	}
+	return nil
+}
+
+// checkOpponents returns an error if the deck of the variant does not have enough cards left to deal holes of the given
+// size to us and every opponent, along with a board of the given size.
+func (o Options) checkOpponents(holeSize, boardSize int) error {
+	opponents := o.getOpponents()
+	if holeSize*(opponents+1)+boardSize+len(o.Dead) > len(o.getVariant().NewDeck().GetCards()) {
+		return fmt.Errorf("not enough cards to deal to %v opponents", opponents)
+	}
+	return nil
+}
+
+// Outcome is the probability that a hole will win against, tie with, and lose to all opponents, as well as its equity,
+// i.e. its expected share of the pot when ties are split evenly among the tying players.
+type Outcome struct {
+	Win    float64 `json:"win"`
+	Tie    float64 `json:"tie"`
+	Lose   float64 `json:"lose"`
+	Equity float64 `json:"equity"`
+}
+
+// getOutcome returns the outcome against the given number of opponents, given the number of holes that are better
+// than, the same as, and worse than the hole. Each opponent is assumed to hold one of those holes independently, i.e.
+// cards held by one opponent are not removed from the holes available to the others, so this is only exact for one
+// opponent.
+func getOutcome(better, same, worse float64, opponents int) Outcome {
+	total := better + same + worse
+	if total == 0 {
+		return Outcome{}
+	}
+	pSame, pWorse := same/total, worse/total
+
+	// We win if every opponent is worse, and don't lose if every opponent is the same or worse
+	win := math.Pow(pWorse, float64(opponents))
+	notLose := math.Pow(pSame+pWorse, float64(opponents))
+
+	// When k opponents tie with us and the rest are worse, we get 1/(k+1) of the pot
+	equity := 0.0
+	coefficient := 1.0
+	for k := 0; k <= opponents; k++ {
+		equity += coefficient * math.Pow(pSame, float64(k)) * math.Pow(pWorse, float64(opponents-k)) / float64(k+1)
+		coefficient = coefficient * float64(opponents-k) / float64(k+1)
+	}
+
+	return Outcome{win, notLose - win, 1 - notLose, equity}
+}
+
+// add adds the other outcome to this one, to later be averaged.
+func (o *Outcome) add(other Outcome) {
+	o.Win += other.Win
+	o.Tie += other.Tie
+	o.Lose += other.Lose
+	o.Equity += other.Equity
+}
+
+// scale scales the outcome by the given factor.
+func (o *Outcome) scale(factor float64) {
+	o.Win *= factor
+	o.Tie *= factor
+	o.Lose *= factor
+	o.Equity *= factor
+}
+
 // level represents a set of holes with the same score, relative to a known board.
 type level struct {
 	score base.Score
@@ -20,19 +122,66 @@ func (l *level) getHoles() [][]base.Card {
 	return l.holes
 }
 
-// evaluate evaluates all possible holes chosen from the given set of cards and sorts them into levels based on their
-// score when combined with the given board. The cards should not overlap with the board (but do not have to constitute
-// a full deck). There must be 3 to 5 cards in the board.
-func evaluate(cards, board []base.Card) []level {
-	holes := base.GetCombinations(cards, 2)
-	hand := make([]base.Card, len(board)+2)
-	copy(hand, board)
+// checkDeck returns an error if any of the cards are not in the deck of the variant.
+func checkDeck(v base.Variant, cards ...[]base.Card) error {
+	deck := base.NewCardSet(v.NewDeck().GetCards()...)
+	for _, set := range cards {
+		for _, c := range set {
+			if !deck.Contains(c) {
+				return fmt.Errorf("card %v is not in the deck", c)
+			}
+		}
+	}
+	return nil
+}
+
+// formatStreets returns the number of cards on the board after each street of the variant, e.g. "3, 4, or 5".
+func formatStreets(v base.Variant) string {
+	streets := v.GetStreets()
+	result := ""
+	for i, street := range streets {
+		if i > 0 && len(streets) > 2 {
+			result += ","
+		}
+		if i > 0 && i == len(streets)-1 {
+			result += " or"
+		}
+		if i > 0 {
+			result += " "
+		}
+		result += strconv.Itoa(street)
+	}
+	return result
+}
+
+// forEachHole calls f with every hole of the given size chosen from the given cards. The same slice is reused for
+// every hole, so f must copy it to keep it.
+func forEachHole(cards []base.Card, size int, f func(hole []base.Card)) {
+	hole := make([]base.Card, size)
+	var choose func(start, i int)
+	choose = func(start, i int) {
+		if i == size {
+			f(hole)
+			return
+		}
+		for j := start; j <= len(cards)-(size-i); j++ {
+			hole[i] = cards[j]
+			choose(j+1, i+1)
+		}
+	}
+	choose(0, 0)
+}
+
+// evaluate evaluates all possible holes of the variant chosen from the given set of cards and sorts them into levels
+// based on their score when combined with the given board. The cards should not overlap with the board (but do not
+// have to constitute a full deck). The board must have been dealt up to some street of the variant.
+func evaluate(cards, board []base.Card, v base.Variant) []level {
+	holes := base.GetCombinations(cards, v.GetHoleSize())
 
 	ranking := make(map[base.Score][][]base.Card)
 	for _, hole := range holes {
-		copy(hand[len(board):], hole)
-		score, _ := base.GetScore(hand)
-		ranking[score] = append(ranking[score], hole)
+		s, _ := v.GetScore(hole, board)
+		ranking[s] = append(ranking[s], hole)
 	}
 
 	scores := []base.Score{}
@@ -48,3 +197,18 @@ func evaluate(cards, board []base.Card) []level {
 
 	return result
 }
+
+// holeClass identifies a set of holes that are identical up to a permutation of suits, e.g. "AKs" or "77".
+type holeClass struct {
+	high   base.Rank
+	low    base.Rank
+	suited bool
+}
+
+func getHoleClass(hole []base.Card) holeClass {
+	high, low := hole[0], hole[1]
+	if high.GetRank() < low.GetRank() {
+		high, low = low, high
+	}
+	return holeClass{high.GetRank(), low.GetRank(), high.GetSuit() == low.GetSuit()}
+}
@@ -6,27 +6,38 @@ import (
 	"github.com/shishichen/strategic-parrot/base"
 )
 
-// GetCurrentOrder returns, given a board of 3 to 5 cards, all other possible coexisting holes sorted into levels
-// from best to worst, where holes in better levels will beat holes in worse levels and holes in the same level will tie,
-// assuming no other cards are dealt to the board, as well as the rank of this hole relative to the ordering.
-// TODO: add number of opponents
-func GetCurrentOrder(hole []base.Card, board []base.Card) ([][][]base.Card, int64, int64, int64, int64, error) {
-	if len(hole) != 2 {
-		return nil, 0, 0, 0, 0, fmt.Errorf("current order can only be returned for holes of 2 cards")
+// GetCurrentOrder returns, given a board dealt up to some street of the variant, e.g. 3 to 5 cards in Texas Hold'em,
+// all other possible coexisting holes sorted into levels from best to worst, where holes in better levels will beat
+// holes in worse levels and holes in the same level will tie, assuming no other cards are dealt to the board, as well
+// as the rank of this hole relative to the ordering and the resulting probability that the hole currently wins, ties,
+// or loses against all opponents. The probability is exact against a single opponent, and against more is sampled
+// from DefaultSamples deals of their holes, seeded so that the result is reproducible.
+func GetCurrentOrder(hole []base.Card, board []base.Card, options Options) ([][][]base.Card, int64, int64, int64, int64,
+	Outcome, error) {
+	variant := options.getVariant()
+	if len(hole) != variant.GetHoleSize() {
+		return nil, 0, 0, 0, 0, Outcome{}, fmt.Errorf("current order can only be returned for holes of %v cards",
+			variant.GetHoleSize())
 	}
-	if len(board) < 3 || len(board) > 5 {
-		return nil, 0, 0, 0, 0, fmt.Errorf("current order can only be returned for boards of 3 to 5 cards")
+	if !base.IsStreet(variant, len(board)) {
+		return nil, 0, 0, 0, 0, Outcome{}, fmt.Errorf("current order can only be returned for boards of %v cards",
+			formatStreets(variant))
 	}
 
-	deck := base.NewDeck()
+	if err := options.checkDead(hole, board); err != nil {
+		return nil, 0, 0, 0, 0, Outcome{}, err
+	}
+
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return nil, 0, 0, 0, 0, Outcome{}, err
+	}
+
+	deck := variant.NewDeck()
 	deck.Remove(hole)
 	deck.Remove(board)
-	levels := evaluate(deck.GetCards(), board)
-
-	hand := make([]base.Card, len(hole)+len(board))
-	copy(hand, hole)
-	copy(hand[len(hole):], board)
-	score, _ := base.GetScore(hand)
+	deck.Remove(options.Dead)
+	levels := evaluate(deck.GetCards(), board, variant)
+	ourScore, _ := variant.GetScore(hole, board)
 
 	result := make([][][]base.Card, len(levels))
 	better, same, worse, total := int64(0), int64(0), int64(0), int64(0)
@@ -35,13 +46,20 @@ func GetCurrentOrder(hole []base.Card, board []base.Card) ([][][]base.Card, int6
 
 		size := int64(len(level.getHoles()))
 		total += size
-		if level.getScore() > score {
+		if level.getScore() > ourScore {
 			better += size
-		} else if level.getScore() == score {
+		} else if level.getScore() == ourScore {
 			same += size
 		} else {
 			worse += size
 		}
 	}
-	return result, better, same, worse, total, nil
+	outcome := getOutcome(float64(better), float64(same), float64(worse), 1)
+	if options.getOpponents() > 1 {
+		var err error
+		if outcome, err = sampleOutcome(hole, board, len(board), options); err != nil {
+			return nil, 0, 0, 0, 0, Outcome{}, err
+		}
+	}
+	return result, better, same, worse, total, outcome, nil
 }
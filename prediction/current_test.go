@@ -0,0 +1,53 @@
+package prediction_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetCurrentOrder(t *testing.T) {
+	// With quads on the board, the ace kicker ties with the 87 of 990 opponent holes holding one of the other 2 aces,
+	// and beats the rest
+	hole, board := cards(t, "AsAh"), cards(t, "KsKhKdKc2c")
+	for opponents := 1; opponents <= 3; opponents++ {
+		options := prediction.Options{Opponents: opponents}
+		levels, better, same, worse, total, got, err := prediction.GetCurrentOrder(hole, board, options)
+		if err != nil {
+			t.Fatalf("GetCurrentOrder() error = %v", err)
+		}
+		if better != 0 || same != 87 || worse != 903 || total != 990 {
+			t.Errorf("GetCurrentOrder() = %v better, %v same, %v worse of %v, want 0, 87, 903 of 990", better, same,
+				worse, total)
+		}
+		count := 0
+		for _, level := range levels {
+			count += len(level)
+		}
+		if count != 990 || len(levels[0]) != 87 {
+			t.Errorf("GetCurrentOrder() has %v holes with %v in the best level, want 990 with 87", count,
+				len(levels[0]))
+		}
+
+		// On the river, the current outcome is the final outcome
+		want, err := prediction.GetFutureOutcomes(hole, board, options)
+		if err != nil {
+			t.Fatalf("GetFutureOutcomes() error = %v", err)
+		}
+		checkOutcome(t, "GetCurrentOrder()", got, want, 1e-9)
+	}
+}
+
+func TestGetCurrentOrderFlop(t *testing.T) {
+	// Top set on a flop with no straight possible is ahead of every other hole
+	levels, better, same, worse, total, got, err := prediction.GetCurrentOrder(cards(t, "KsKh"),
+		cards(t, "Kd8s2s"), prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetCurrentOrder() error = %v", err)
+	}
+	if better != 0 || same != 0 || worse != 1081 || total != 1081 || len(levels) == 0 {
+		t.Errorf("GetCurrentOrder() = %v better, %v same, %v worse of %v, want 0, 0, 1081 of 1081", better, same,
+			worse, total)
+	}
+	checkOutcome(t, "GetCurrentOrder()", got, prediction.Outcome{Win: 1, Equity: 1}, 1e-9)
+}
@@ -8,75 +8,92 @@ import (
 	"github.com/shishichen/strategic-parrot/base"
 )
 
-// GetFutureOutcomes returns, given a hole and board of 3 to 5 cards, the probability that the hole will win, tie,
-// and lose at the end of the game, assumming random subsequent cards.
-// TODO: add number of opponents
-func GetFutureOutcomes(hole []base.Card, board []base.Card) (float64, float64, float64, error) {
-	if len(hole) != 2 {
-		return 0, 0, 0, fmt.Errorf("future outcomes can only be predicted for holes with 2 cards")
+// GetFutureOutcomes returns, given a hole and board dealt up to some street of the variant, e.g. 3 to 5 cards in Texas
+// Hold'em, the probability that the hole will win, tie, and lose against all opponents at the end of the game,
+// assumming random subsequent cards. This is exact against a single opponent. Against more, enumerating every
+// combination of their holes is infeasible, so DefaultSamples games are sampled instead, seeded so that the result is
+// reproducible.
+func GetFutureOutcomes(hole []base.Card, board []base.Card, options Options) (Outcome, error) {
+	variant := options.getVariant()
+	if len(hole) != variant.GetHoleSize() {
+		return Outcome{}, fmt.Errorf("future outcomes can only be predicted for holes with %v cards",
+			variant.GetHoleSize())
 	}
 	if len(board) == 0 {
-		return 0, 0, 0, fmt.Errorf("call GetInitialOutcomes to get outcomes for an empty board ")
+		return Outcome{}, fmt.Errorf("call GetInitialOutcomes to get outcomes for an empty board ")
 	}
-	if len(board) < 3 || len(board) > 5 {
-		return 0, 0, 0, fmt.Errorf("future outcomes can only be predicted for boards with 3 to 5 cards")
+	if !base.IsStreet(variant, len(board)) {
+		return Outcome{}, fmt.Errorf("future outcomes can only be predicted for boards with %v cards",
+			formatStreets(variant))
 	}
 
-	deck := base.NewDeck()
+	return getFutureOutcomes(hole, board, options)
+}
+
+// getFutureOutcomes returns the future outcomes of the hole, where the board may also be empty, in which case every
+// possible board is enumerated against a single opponent.
+func getFutureOutcomes(hole []base.Card, board []base.Card, options Options) (Outcome, error) {
+	variant := options.getVariant()
+	if err := options.checkDead(hole, board); err != nil {
+		return Outcome{}, err
+	}
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return Outcome{}, err
+	}
+	if options.getOpponents() > 1 {
+		return sampleOutcome(hole, board, base.GetBoardSize(variant), options)
+	}
+
+	deck := variant.NewDeck()
 	deck.Remove(hole)
 	deck.Remove(board)
-	subsequent := base.GetCombinations(deck.GetCards(), 5-len(board))
+	deck.Remove(options.Dead)
+	subsequent := base.GetCombinations(deck.GetCards(), base.GetBoardSize(variant)-len(board))
 
 	n := runtime.NumCPU()
 	var wg sync.WaitGroup
-	better, same, worse, total := make([]int64, n), make([]int64, n), make([]int64, n), make([]int64, n)
+	outcomes := make([]Outcome, n)
 	for id := 0; id < n; id++ {
 		wg.Add(1)
 		go func(id int) {
 			defer wg.Done()
 
-			futureBoard := make([]base.Card, 5)
+			futureBoard := make([]base.Card, base.GetBoardSize(variant))
 			copy(futureBoard, board)
 
-			futureHand := make([]base.Card, len(hole)+5)
-			copy(futureHand, hole)
-			copy(futureHand[len(hole):], board)
-
 			lower := id * len(subsequent) / n
 			upper := (id + 1) * len(subsequent) / n
 			for i := lower; i < upper; i++ {
-				deck := base.NewDeck()
+				deck := variant.NewDeck()
 				deck.Remove(hole)
 				deck.Remove(board)
+				deck.Remove(options.Dead)
 				deck.Remove(subsequent[i])
 				copy(futureBoard[len(board):], subsequent[i])
-				levels := evaluate(deck.GetCards(), futureBoard)
-
-				copy(futureHand[len(hole)+len(board):], subsequent[i])
-				score, _ := base.GetScore(futureHand)
+				score, _ := variant.GetScore(hole, futureBoard)
 
-				for _, level := range levels {
-					size := int64(len(level.getHoles()))
-					total[id] += size
-					if level.getScore() > score {
-						better[id] += size
-					} else if level.getScore() == score {
-						same[id] += size
+				better, same, worse := 0, 0, 0
+				forEachHole(deck.GetCards(), len(hole), func(opponentHole []base.Card) {
+					opponentScore, _ := variant.GetScore(opponentHole, futureBoard)
+					if opponentScore > score {
+						better++
+					} else if opponentScore == score {
+						same++
 					} else {
-						worse[id] += size
+						worse++
 					}
-				}
+				})
+				// Every subsequent set of cards is equally likely, so their outcomes can simply be averaged
+				outcomes[id].add(getOutcome(float64(better), float64(same), float64(worse), 1))
 			}
 		}(id)
 	}
 	wg.Wait()
 
-	betterTotal, sameTotal, worseTotal, totalTotal := int64(0), int64(0), int64(0), int64(0)
+	result := Outcome{}
 	for id := 0; id < n; id++ {
-		betterTotal += better[id]
-		sameTotal += same[id]
-		worseTotal += worse[id]
-		totalTotal += total[id]
+		result.add(outcomes[id])
 	}
-	return float64(worseTotal) / float64(totalTotal), float64(sameTotal) / float64(totalTotal), float64(betterTotal) / float64(totalTotal), nil
+	result.scale(1 / float64(len(subsequent)))
+	return result, nil
 }
@@ -0,0 +1,121 @@
+package prediction_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+// checkOutcome checks that the outcome sums to 1 and is within the tolerance of the wanted outcome.
+func checkOutcome(t *testing.T, name string, got, want prediction.Outcome, tolerance float64) {
+	t.Helper()
+	if math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 {
+		t.Errorf("%v = %+v, which doesn't sum to 1", name, got)
+	}
+	if math.Abs(got.Win-want.Win) > tolerance || math.Abs(got.Tie-want.Tie) > tolerance ||
+		math.Abs(got.Lose-want.Lose) > tolerance || math.Abs(got.Equity-want.Equity) > tolerance {
+		t.Errorf("%v = %+v, want %+v", name, got, want)
+	}
+}
+
+func TestGetFutureOutcomes(t *testing.T) {
+	// With quads on the board, the ace kicker ties with the 87 of 990 opponent holes holding one of the other 2 aces,
+	// and beats the rest
+	same, worse := 87.0/990, 903.0/990
+	// Of the 990 * 903 deals to 2 opponents, 903 * 820 hold no ace, 86 * 42 hold one ace each, and the rest hold one
+	// or both aces between them, which ties only one opponent
+	deals := 990.0 * 903
+	none, both := 903*820/deals, 86*42/deals
+	one := 1 - none - both
+
+	tests := []struct {
+		name      string
+		hole      string
+		board     string
+		opponents int
+		want      prediction.Outcome
+	}{
+		{"nuts", "AsKs", "QsJsTs2c3d", 1, prediction.Outcome{Win: 1, Equity: 1}},
+		{"nuts against 3", "AsKs", "QsJsTs2c3d", 3, prediction.Outcome{Win: 1, Equity: 1}},
+		{"board plays", "7c2d", "AsKsQsJsTs", 1, prediction.Outcome{Tie: 1, Equity: 1.0 / 2}},
+		{"board plays against 3", "7c2d", "AsKsQsJsTs", 3, prediction.Outcome{Tie: 1, Equity: 1.0 / 4}},
+		{"kicker", "AsAh", "KsKhKdKc2c", 1, prediction.Outcome{Win: worse, Tie: same, Equity: worse + same/2}},
+		{"kicker against 2", "AsAh", "KsKhKdKc2c", 2, prediction.Outcome{Win: none, Tie: one + both,
+			Equity: none + one/2 + both/3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetFutureOutcomes(cards(t, tt.hole), cards(t, tt.board),
+				prediction.Options{Opponents: tt.opponents})
+			if err != nil {
+				t.Fatalf("GetFutureOutcomes() error = %v", err)
+			}
+			// Against more than one opponent, outcomes are sampled
+			tolerance := 1e-9
+			if tt.opponents > 1 {
+				tolerance = 0.005
+			}
+			checkOutcome(t, "GetFutureOutcomes()", got, tt.want, tolerance)
+		})
+	}
+}
+
+func TestGetFutureOutcomesOpponents(t *testing.T) {
+	hole, board := cards(t, "AhAd"), cards(t, "Kc7s2d9h")
+	previous := prediction.Outcome{Win: 1, Equity: 1}
+	for opponents := 1; opponents <= 4; opponents++ {
+		got, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{Opponents: opponents})
+		if err != nil {
+			t.Fatalf("GetFutureOutcomes() error = %v", err)
+		}
+		if math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 {
+			t.Errorf("GetFutureOutcomes() against %v = %+v, which doesn't sum to 1", opponents, got)
+		}
+		if got.Win >= previous.Win || got.Equity >= previous.Equity {
+			t.Errorf("GetFutureOutcomes() against %v = %+v, want less than %+v", opponents, got, previous)
+		}
+		// Sampled outcomes are seeded, so they are always the same
+		again, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{Opponents: opponents})
+		if err != nil || again != got {
+			t.Errorf("GetFutureOutcomes() against %v again = %+v, %v, want %+v", opponents, again, err, got)
+		}
+		previous = got
+	}
+}
+
+func TestGetFutureOutcomesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+	}{
+		{"empty board", "AsKs", ""},
+		{"short board", "AsKs", "2c3d"},
+		{"long board", "AsKs", "2c3d4h5s6c7d"},
+		{"short hole", "As", "2c3d4h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := prediction.GetFutureOutcomes(cards(t, tt.hole), cards(t, tt.board),
+				prediction.Options{}); err == nil {
+				t.Errorf("GetFutureOutcomes() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestGetFutureOutcomesDeadCards(t *testing.T) {
+	// With the ace of clubs dead, the ace kicker only ties with the 43 of 946 opponent holes holding the last ace
+	hole, board := cards(t, "AsAh"), cards(t, "KsKhKdKc2c")
+	got, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{Dead: cards(t, "Ac")})
+	if err != nil {
+		t.Fatalf("GetFutureOutcomes() error = %v", err)
+	}
+	checkOutcome(t, "GetFutureOutcomes()", got, prediction.Outcome{Win: 903.0 / 946, Tie: 43.0 / 946,
+		Equity: (903 + 43.0/2) / 946}, 1e-9)
+
+	if _, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{Dead: cards(t, "As")}); err == nil {
+		t.Errorf("GetFutureOutcomes() with a dead card in the hole succeeded, want error")
+	}
+}
@@ -0,0 +1,325 @@
+package prediction
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// HiLoOutcome is the expected result of a hand in a hi-lo split pot game, where the best high hand and the best
+// qualifying 8-or-better low hand each win half of the pot, and the best high hand wins all of it if nobody has a
+// qualifying low. Tied hands split their half, so a hand that ties for low with one opponent is quartered. Shares are
+// fractions of the whole pot.
+type HiLoOutcome struct {
+	High   float64 `json:"high"`   // expected share of the pot won with the high hand
+	Low    float64 `json:"low"`    // expected share of the pot won with the low hand
+	Equity float64 `json:"equity"` // expected share of the whole pot, the sum of the high and low shares
+	Scoop  float64 `json:"scoop"`  // probability of winning the whole pot without sharing any of it
+}
+
+func (o *HiLoOutcome) add(other HiLoOutcome) {
+	o.High += other.High
+	o.Low += other.Low
+	o.Equity += other.Equity
+	o.Scoop += other.Scoop
+}
+
+func (o *HiLoOutcome) scale(factor float64) {
+	o.High *= factor
+	o.Low *= factor
+	o.Equity *= factor
+	o.Scoop *= factor
+}
+
+// HiLoEstimate is a hi-lo outcome estimated by sampling random games, along with a 95% confidence interval.
+type HiLoEstimate struct {
+	HiLoOutcome
+	EquityInterval Interval
+	// StandardError is the standard error of the equity.
+	StandardError float64
+	// Samples is the number of games sampled.
+	Samples int
+}
+
+// lowScorer scores the low hand of a hole combined with a board, according to the rules of a variant of poker.
+type lowScorer func(hole, board []base.Card) (base.LowScore, error)
+
+// getHoldemLowScore scores the low hand of a hole combined with a board in Texas Hold'em, where any 5 of the cards can
+// be used.
+func getHoldemLowScore(hole, board []base.Card) (base.LowScore, error) {
+	var hand [7]base.Card
+	n := copy(hand[:], hole)
+	n += copy(hand[n:], board)
+	return base.GetLowScore(hand[:n])
+}
+
+// getLowScorer returns the low scorer of a variant that can be played hi-lo, i.e. Texas Hold'em or Omaha.
+func getLowScorer(v base.Variant) (lowScorer, error) {
+	switch v.(type) {
+	case base.TexasHoldem:
+		return getHoldemLowScore, nil
+	case base.Omaha, base.FiveCardOmaha:
+		return base.GetOmahaLowScore, nil
+	}
+	return nil, fmt.Errorf("hi-lo outcomes can only be predicted for Texas Hold'em and Omaha")
+}
+
+// GetHiLoFutureOutcomes returns, given a hole and a board dealt up to some street of the variant, e.g. 3 to 5 cards in
+// Texas Hold'em, the expected share of a hi-lo split pot that the hole will win against all opponents at the end of
+// the game, assumming random subsequent cards. In Omaha Hi-Lo, the low hand, like the high hand, must be made of
+// exactly 2 cards from the hole and 3 from the board. Like GetFutureOutcomes, this is exact against a single opponent,
+// which can take minutes on the Omaha flop, and samples DefaultSamples games against more, seeded so that the result
+// is reproducible.
+func GetHiLoFutureOutcomes(hole []base.Card, board []base.Card, options Options) (HiLoOutcome, error) {
+	variant := options.getVariant()
+	lowScore, err := getLowScorer(variant)
+	if err != nil {
+		return HiLoOutcome{}, err
+	}
+	if len(hole) != variant.GetHoleSize() {
+		return HiLoOutcome{}, fmt.Errorf("future outcomes can only be predicted for holes with %v cards",
+			variant.GetHoleSize())
+	}
+	if len(board) == 0 {
+		return HiLoOutcome{}, fmt.Errorf("call EstimateHiLoOutcomes to get hi-lo outcomes for an empty board")
+	}
+	if !base.IsStreet(variant, len(board)) {
+		return HiLoOutcome{}, fmt.Errorf("future outcomes can only be predicted for boards with %v cards",
+			formatStreets(variant))
+	}
+
+	if err := options.checkDead(hole, board); err != nil {
+		return HiLoOutcome{}, err
+	}
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return HiLoOutcome{}, err
+	}
+	if options.getOpponents() > 1 {
+		estimate, err := EstimateHiLoOutcomes(hole, board, options,
+			Sampling{Rand: rand.New(rand.NewSource(fallbackSeed))})
+		return estimate.HiLoOutcome, err
+	}
+
+	deck := variant.NewDeck()
+	deck.Remove(hole)
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	subsequent := base.GetCombinations(deck.GetCards(), base.GetBoardSize(variant)-len(board))
+
+	n := runtime.NumCPU()
+	var wg sync.WaitGroup
+	outcomes := make([]HiLoOutcome, n)
+	for id := 0; id < n; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			futureBoard := make([]base.Card, base.GetBoardSize(variant))
+			copy(futureBoard, board)
+
+			lower := id * len(subsequent) / n
+			upper := (id + 1) * len(subsequent) / n
+			for i := lower; i < upper; i++ {
+				deck := variant.NewDeck()
+				deck.Remove(hole)
+				deck.Remove(board)
+				deck.Remove(options.Dead)
+				deck.Remove(subsequent[i])
+				copy(futureBoard[len(board):], subsequent[i])
+
+				high, _ := variant.GetScore(hole, futureBoard)
+				low, _ := lowScore(hole, futureBoard)
+
+				// Count the opponent holes by how their high and low hands compare to ours
+				var counts [3][3]float64
+				forEachHole(deck.GetCards(), len(hole), func(opponentHole []base.Card) {
+					opponentHigh, _ := variant.GetScore(opponentHole, futureBoard)
+					opponentLow, _ := lowScore(opponentHole, futureBoard)
+					counts[compare(uint64(opponentHigh), uint64(high))][compare(uint64(opponentLow), uint64(low))]++
+				})
+				// Every subsequent set of cards is equally likely, so their outcomes can simply be averaged
+				outcomes[id].add(getHiLoOutcome(counts, low != 0))
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	result := HiLoOutcome{}
+	for id := 0; id < n; id++ {
+		result.add(outcomes[id])
+	}
+	result.scale(1 / float64(len(subsequent)))
+	return result, nil
+}
+
+// EstimateHiLoOutcomes returns, given a hole and a board that is empty or dealt up to some street of the variant, an
+// estimate of the expected share of a hi-lo split pot that the hole will win against all opponents at the end of the
+// game, assumming random subsequent cards, by sampling random games dealt to every opponent from the same deck.
+func EstimateHiLoOutcomes(hole []base.Card, board []base.Card, options Options, sampling Sampling) (HiLoEstimate,
+	error) {
+	variant := options.getVariant()
+	lowScore, err := getLowScorer(variant)
+	if err != nil {
+		return HiLoEstimate{}, err
+	}
+	if len(hole) != variant.GetHoleSize() {
+		return HiLoEstimate{}, fmt.Errorf("hi-lo outcomes can only be estimated for holes with %v cards",
+			variant.GetHoleSize())
+	}
+	if len(board) != 0 && !base.IsStreet(variant, len(board)) {
+		return HiLoEstimate{}, fmt.Errorf("outcomes can only be estimated for boards with 0 or %v cards",
+			formatStreets(variant))
+	}
+	if err := options.checkDead(hole, board); err != nil {
+		return HiLoEstimate{}, err
+	}
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return HiLoEstimate{}, err
+	}
+	size := base.GetBoardSize(variant)
+	if err := options.checkOpponents(len(hole), size); err != nil {
+		return HiLoEstimate{}, err
+	}
+	opponents := options.getOpponents()
+
+	samples := sampling.Samples
+	if samples <= 0 {
+		samples = DefaultSamples
+	}
+	r := sampling.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	deck := variant.NewDeck()
+	deck.Remove(hole)
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	cards := deck.GetCards()
+	dealt := size - len(board)
+	needed := dealt + len(hole)*opponents
+
+	futureBoard := make([]base.Card, size)
+	copy(futureBoard, board)
+	highs := make([]base.Score, opponents+1)
+	lows := make([]base.LowScore, opponents+1)
+
+	t := tally{}
+	for t.samples < samples {
+		// Only shuffle as many cards as will be dealt
+		for j := 0; j < needed; j++ {
+			k := j + r.Intn(len(cards)-j)
+			cards[j], cards[k] = cards[k], cards[j]
+		}
+		copy(futureBoard[len(board):], cards[:dealt])
+		highs[0], _ = variant.GetScore(hole, futureBoard)
+		lows[0], _ = lowScore(hole, futureBoard)
+		for j := 1; j <= opponents; j++ {
+			start := dealt + len(hole)*(j-1)
+			highs[j], _ = variant.GetScore(cards[start:start+len(hole)], futureBoard)
+			lows[j], _ = lowScore(cards[start:start+len(hole)], futureBoard)
+		}
+
+		t.addHiLo(splitPot(highs, lows))
+		if sampling.StandardError > 0 && t.samples%sampleBatch == 0 &&
+			t.getStandardError() <= sampling.StandardError {
+			break
+		}
+	}
+	return t.getHiLoEstimate(), nil
+}
+
+// compare returns 0 if the opponent's score is better than ours, 1 if it is the same, and 2 if it is worse.
+func compare(opponent, ours uint64) int {
+	if opponent > ours {
+		return 0
+	} else if opponent == ours {
+		return 1
+	}
+	return 2
+}
+
+// getHiLoOutcome returns the expected share of a hi-lo split pot against a single opponent, given the number of
+// opponent holes whose high and low hands are better than, the same as, and worse than ours, indexed as returned by
+// compare. When we have no qualifying low, every opponent with one counts as having a better low.
+func getHiLoOutcome(counts [3][3]float64, qualifies bool) HiLoOutcome {
+	total := 0.0
+	for _, row := range counts {
+		for _, count := range row {
+			total += count
+		}
+	}
+	if total == 0 {
+		return HiLoOutcome{}
+	}
+
+	// The opponent beats us, ties us, or doesn't, for each of high and low
+	ties := [3]int{-1, 1, 0}
+	result := HiLoOutcome{}
+	for high, row := range counts {
+		for low, count := range row {
+			p := count / total
+			highShare, lowShare := getShares(ties[high], ties[low], qualifies)
+			result.High += p * highShare
+			result.Low += p * lowShare
+			if highShare+lowShare == 1 {
+				result.Scoop += p
+			}
+		}
+	}
+	result.Equity = result.High + result.Low
+	return result
+}
+
+// getShares returns our share of the pot won with the high and low hands, given the number of opponents tying us for
+// high and for low, with -1 meaning that some opponent beats us, and whether we have a qualifying low.
+func getShares(highTies, lowTies int, qualifies bool) (float64, float64) {
+	highShare := 0.0
+	if highTies >= 0 {
+		highShare = 1 / float64(highTies+1)
+	}
+	// Without a qualifying low, the pot is only split if some opponent has one, in which case they beat us for low
+	if !qualifies && lowTies >= 0 {
+		return highShare, 0
+	}
+	lowShare := 0.0
+	if qualifies && lowTies >= 0 {
+		lowShare = 1 / float64(lowTies+1)
+	}
+	return highShare / 2, lowShare / 2
+}
+
+// splitPot returns our share of a hi-lo split pot in a single game, given the high and low scores of every player,
+// starting with ours.
+func splitPot(highs []base.Score, lows []base.LowScore) HiLoOutcome {
+	highTies, lowTies := 0, 0
+	for i := 1; i < len(highs); i++ {
+		if highTies >= 0 {
+			switch compare(uint64(highs[i]), uint64(highs[0])) {
+			case 0:
+				highTies = -1
+			case 1:
+				highTies++
+			}
+		}
+		if lowTies >= 0 {
+			switch compare(uint64(lows[i]), uint64(lows[0])) {
+			case 0:
+				lowTies = -1
+			case 1:
+				lowTies++
+			}
+		}
+	}
+
+	highShare, lowShare := getShares(highTies, lowTies, lows[0] != 0)
+	result := HiLoOutcome{High: highShare, Low: lowShare, Equity: highShare + lowShare}
+	if result.Equity == 1 {
+		result.Scoop = 1
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+package prediction_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+// checkHiLoOutcome checks that the shares add up and are within the tolerance of the wanted outcome.
+func checkHiLoOutcome(t *testing.T, name string, got, want prediction.HiLoOutcome, tolerance float64) {
+	t.Helper()
+	if math.Abs(got.High+got.Low-got.Equity) > 1e-9 {
+		t.Errorf("%v = %+v, whose shares don't add up to its equity", name, got)
+	}
+	if math.Abs(got.High-want.High) > tolerance || math.Abs(got.Low-want.Low) > tolerance ||
+		math.Abs(got.Equity-want.Equity) > tolerance || math.Abs(got.Scoop-want.Scoop) > tolerance {
+		t.Errorf("%v = %+v, want %+v", name, got, want)
+	}
+}
+
+func TestGetHiLoFutureOutcomes(t *testing.T) {
+	tests := []struct {
+		name      string
+		hole      string
+		board     string
+		opponents int
+		want      prediction.HiLoOutcome
+	}{
+		// Nobody has a low, so the high hands split the whole pot
+		{"board plays", "7c2d", "AsKsQsJsTs", 1, prediction.HiLoOutcome{High: 1.0 / 2, Equity: 1.0 / 2}},
+		{"board plays against 3", "7c2d", "AsKsQsJsTs", 3, prediction.HiLoOutcome{High: 1.0 / 4, Equity: 1.0 / 4}},
+		// The steel wheel makes the nut low, and loses the high only to 7h6h, but is quartered by the 9 holes of an
+		// ace and a deuce
+		{"steel wheel", "Ah2h", "3h4h5hKcQd", 1, prediction.HiLoOutcome{High: 989.0 / 2 / 990,
+			Low: (0.5 + 9*0.25 + 980*0.5) / 990, Equity: (0.5 + 9*0.75 + 980) / 990, Scoop: 980.0 / 990}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetHiLoFutureOutcomes(cards(t, tt.hole), cards(t, tt.board),
+				prediction.Options{Opponents: tt.opponents})
+			if err != nil {
+				t.Fatalf("GetHiLoFutureOutcomes() error = %v", err)
+			}
+			checkHiLoOutcome(t, "GetHiLoFutureOutcomes()", got, tt.want, 1e-9)
+		})
+	}
+}
+
+func TestGetHiLoFutureOutcomesWithoutLow(t *testing.T) {
+	// No low can qualify with only 1 more card, so the outcome is the same as for the high hand alone
+	hole, board := cards(t, "AhQh"), cards(t, "KsKhQdJc")
+	for opponents := 1; opponents <= 2; opponents++ {
+		options := prediction.Options{Opponents: opponents}
+		high, err := prediction.GetFutureOutcomes(hole, board, options)
+		if err != nil {
+			t.Fatalf("GetFutureOutcomes() error = %v", err)
+		}
+		got, err := prediction.GetHiLoFutureOutcomes(hole, board, options)
+		if err != nil {
+			t.Fatalf("GetHiLoFutureOutcomes() error = %v", err)
+		}
+		want := prediction.HiLoOutcome{High: high.Equity, Equity: high.Equity, Scoop: high.Win}
+		checkHiLoOutcome(t, "GetHiLoFutureOutcomes()", got, want, 1e-9)
+	}
+}
+
+func TestGetHiLoFutureOutcomesOmaha(t *testing.T) {
+	options := prediction.Options{Variant: base.Omaha{}}
+
+	// Omaha hi-lo holes must use exactly 2 cards, so 2 low cards can't make a low with a board of 2 low cards, and the
+	// outcome is the same as for the high hand alone
+	hole, board := cards(t, "Ac2cKdKh"), cards(t, "3s4sQhJcTd")
+	high, err := prediction.GetFutureOutcomes(hole, board, options)
+	if err != nil {
+		t.Fatalf("GetFutureOutcomes() error = %v", err)
+	}
+	got, err := prediction.GetHiLoFutureOutcomes(hole, board, options)
+	if err != nil {
+		t.Fatalf("GetHiLoFutureOutcomes() error = %v", err)
+	}
+	want := prediction.HiLoOutcome{High: high.Equity, Equity: high.Equity, Scoop: high.Win}
+	checkHiLoOutcome(t, "GetHiLoFutureOutcomes()", got, want, 1e-9)
+
+	// With 3 low cards on the board, the exact outcome agrees with sampled games
+	hole, board = cards(t, "Ah2h7c9d"), cards(t, "3s4s8dKcQh")
+	want, err = prediction.GetHiLoFutureOutcomes(hole, board, options)
+	if err != nil {
+		t.Fatalf("GetHiLoFutureOutcomes() error = %v", err)
+	}
+	if want.Low == 0 {
+		t.Errorf("GetHiLoFutureOutcomes() = %+v, want a low share", want)
+	}
+	estimate, err := prediction.EstimateHiLoOutcomes(hole, board, options,
+		prediction.Sampling{Samples: 20000, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatalf("EstimateHiLoOutcomes() error = %v", err)
+	}
+	if math.Abs(estimate.Equity-want.Equity) > 4*estimate.StandardError {
+		t.Errorf("EstimateHiLoOutcomes() equity = %v with standard error %v, want %v", estimate.Equity,
+			estimate.StandardError, want.Equity)
+	}
+}
+
+func TestEstimateHiLoOutcomes(t *testing.T) {
+	hole, board := cards(t, "Ah2h"), cards(t, "3h4h5hKc")
+	want, err := prediction.GetHiLoFutureOutcomes(hole, board, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetHiLoFutureOutcomes() error = %v", err)
+	}
+	got, err := prediction.EstimateHiLoOutcomes(hole, board, prediction.Options{},
+		prediction.Sampling{Samples: 20000, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatalf("EstimateHiLoOutcomes() error = %v", err)
+	}
+	checkHiLoOutcome(t, "EstimateHiLoOutcomes()", got.HiLoOutcome, want, 0.01)
+	if math.Abs(got.Equity-want.Equity) > 4*got.StandardError {
+		t.Errorf("EstimateHiLoOutcomes() equity = %v with standard error %v, want %v", got.Equity,
+			got.StandardError, want.Equity)
+	}
+
+	// Omaha hi-lo holes must use exactly 2 cards, so 2 low cards can't make a low with a board of 2 low cards
+	omaha, err := prediction.EstimateHiLoOutcomes(cards(t, "Ac2cKdKh"), cards(t, "3s4sQhJcTd"),
+		prediction.Options{Variant: base.Omaha{}},
+		prediction.Sampling{Samples: 1000, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatalf("EstimateHiLoOutcomes() error = %v", err)
+	}
+	if omaha.Low != 0 {
+		t.Errorf("EstimateHiLoOutcomes() = %+v, want no low", omaha)
+	}
+}
+
+func TestHiLoOutcomesErrors(t *testing.T) {
+	for _, board := range []string{"", "2c3d", "2c3d4h5s6c7d"} {
+		if _, err := prediction.GetHiLoFutureOutcomes(cards(t, "AsKs"), cards(t, board),
+			prediction.Options{}); err == nil {
+			t.Errorf("GetHiLoFutureOutcomes() with board %q succeeded, want error", board)
+		}
+	}
+	if _, err := prediction.EstimateHiLoOutcomes(cards(t, "AsKsQs"), nil, prediction.Options{},
+		prediction.Sampling{}); err == nil {
+		t.Errorf("EstimateHiLoOutcomes() of 3 cards succeeded, want error")
+	}
+	// Short-deck isn't played hi-lo
+	if _, err := prediction.GetHiLoFutureOutcomes(cards(t, "AsKs"), cards(t, "6c7d8h"),
+		prediction.Options{Variant: base.ShortDeckHoldem{}}); err == nil {
+		t.Errorf("GetHiLoFutureOutcomes() in short-deck succeeded, want error")
+	}
+}
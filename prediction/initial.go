@@ -3,10 +3,15 @@ package prediction
 import (
 	"bufio"
 	"bytes"
+	"embed"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"math/rand"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,33 +19,180 @@ import (
 	"github.com/shishichen/strategic-parrot/base"
 )
 
-const initialOutcomeFile = "data/initial_outcomes"
+// MaxOpponents is the largest number of opponents that initial outcomes are available for.
+const MaxOpponents = 9
 
-// GetInitialOutcomes returns, given a hole and empty board, the probability that the hole will win, tie, and lose at
-// the end of the game, assumming random subsequent cards. i.e. the starting hand probabilities, which are always the
-// same.
-// TODO: add number of opponents
-func GetInitialOutcomes(hole []base.Card) (float64, float64, float64, error) {
-	if len(hole) != 2 {
-		return 0, 0, 0, fmt.Errorf("initial outcomes can only be predicted for holes with 2 cards")
+// initialOutcomeFile is the embedded file containing the initial outcomes for a given number of opponents.
+const initialOutcomeFile = "data/initial_outcomes_%d"
+
+//go:embed data/initial_outcomes_*
+var initialOutcomeFiles embed.FS
+
+// initialOutcomeTable is the table of initial outcomes for a number of opponents. It is parsed from the embedded file
+// the first time it is needed, unless it has already been replaced by LoadInitialOutcomes.
+type initialOutcomeTable struct {
+	once     sync.Once
+	mu       sync.RWMutex
+	outcomes map[base.Key]Outcome
+	err      error
+}
+
+var initialOutcomeTables [MaxOpponents]initialOutcomeTable
+
+// initialOutcomeSamples is the number of games simulated per hole when precomputing initial outcomes for more than one
+// opponent, where enumerating every possible game is infeasible.
+const initialOutcomeSamples = 50000
+
+// verifyTolerance is how far outcomes can be from exact while still passing verification, to allow for rounding.
+const verifyTolerance = 1e-9
+
+// GetInitialOutcomes returns, given a hole and empty board, the probability that the hole will win, tie, and lose
+// against all opponents at the end of the game, assumming random subsequent cards. i.e. the starting hand
+// probabilities, which are always the same. Texas Hold'em uses precomputed tables, while other variants with holes of 2
+// cards enumerate every board as GetFutureOutcomes does against a single opponent, and variants with larger holes have
+// too many opponent holes to enumerate at all. The tables assume a full deck, so with dead cards, Texas Hold'em instead
+// samples DefaultSamples games from the remaining deck, seeded so that the result is reproducible.
+func GetInitialOutcomes(hole []base.Card, options Options) (Outcome, error) {
+	variant := options.getVariant()
+	if len(hole) != variant.GetHoleSize() {
+		return Outcome{}, fmt.Errorf("initial outcomes can only be predicted for holes with %v cards",
+			variant.GetHoleSize())
+	}
+	if _, ok := variant.(base.TexasHoldem); !ok {
+		if len(hole) != 2 {
+			return Outcome{}, fmt.Errorf("call EstimateOutcomes to get initial outcomes for holes with %v cards",
+				len(hole))
+		}
+		return getFutureOutcomes(hole, []base.Card{}, options)
+	}
+	if len(options.Dead) > 0 {
+		estimate, err := EstimateOutcomes(hole, []base.Card{}, options,
+			Sampling{Rand: rand.New(rand.NewSource(fallbackSeed))})
+		return estimate.Outcome, err
+	}
+	opponents := options.getOpponents()
+	if opponents > MaxOpponents {
+		return Outcome{}, fmt.Errorf("initial outcomes can only be predicted for up to %v opponents", MaxOpponents)
 	}
 
-	outcomes, err := readInitialOutcomes()
+	outcomes, err := getInitialOutcomeTable(opponents)
 	if err != nil {
-		return 0, 0, 0, err
+		return Outcome{}, err
 	}
 
 	key, _ := base.GetKey(hole)
 	outcome, ok := outcomes[key]
 	if !ok {
-		return 0, 0, 0, fmt.Errorf("initial outcome for hole %v not found", hole)
+		return Outcome{}, fmt.Errorf("initial outcome for hole %v not found", hole)
+	}
+
+	return outcome, nil
+}
+
+// LoadInitialOutcomes replaces the initial outcomes for the given number of opponents with a custom table, read in the
+// format written by PrecomputeInitialOutcomes.
+func LoadInitialOutcomes(opponents int, r io.Reader) error {
+	if opponents < 1 || opponents > MaxOpponents {
+		return fmt.Errorf("initial outcomes can only be loaded for 1 to %v opponents", MaxOpponents)
+	}
+	outcomes, err := parseInitialOutcomes(r)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the embedded table is never parsed afterwards
+	t := &initialOutcomeTables[opponents-1]
+	t.once.Do(func() {})
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.outcomes, t.err = outcomes, nil
+	return nil
+}
+
+// LoadInitialOutcomesFile replaces the initial outcomes for the given number of opponents with a custom table, read
+// from a file in the format written by PrecomputeInitialOutcomes.
+func LoadInitialOutcomesFile(opponents int, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
 	}
+	defer file.Close()
 
-	return outcome.win, outcome.tie, outcome.lose, nil
+	return LoadInitialOutcomes(opponents, file)
 }
 
-// PrecomputeInitialOutcomes precomputes the initial outcomes and stores them in a file for GetInitialOutcomes to later use.
-func PrecomputeInitialOutcomes() error {
+// PrecomputeInitialOutcomes precomputes the initial outcomes for the given number of opponents and writes them in the
+// format of the file that GetInitialOutcomes later uses. Outcomes for a single opponent are computed exactly by
+// enumerating every board, while outcomes for more opponents are estimated by simulating random games from the given
+// seed, so that the same seed always gives the same outcomes. Progress is logged as it goes.
+func PrecomputeInitialOutcomes(opponents int, seed int64, w io.Writer) error {
+	if opponents < 1 || opponents > MaxOpponents {
+		return fmt.Errorf("initial outcomes can only be precomputed for 1 to %v opponents", MaxOpponents)
+	}
+
+	var outcomes map[base.Key]Outcome
+	if opponents == 1 {
+		outcomes = enumerateInitialOutcomes()
+	} else {
+		outcomes = simulateInitialOutcomes(opponents, seed)
+	}
+	return writeInitialOutcomes(w, outcomes)
+}
+
+// VerifyInitialOutcomes reads initial outcomes in the format written by PrecomputeInitialOutcomes, and returns every
+// problem found with them: missing holes, probabilities that don't sum to 1, and holes that are identical up to a
+// permutation of suits but have different outcomes.
+func VerifyInitialOutcomes(r io.Reader) []error {
+	outcomes, err := parseInitialOutcomes(r)
+	if err != nil {
+		return []error{err}
+	}
+
+	result := []error{}
+	holes := base.GetCombinations(base.NewDeck().GetCards(), 2)
+	if len(outcomes) != len(holes) {
+		result = append(result, fmt.Errorf("found outcomes for %v holes, want %v", len(outcomes), len(holes)))
+	}
+
+	classes := make(map[holeClass]Outcome)
+	valid := make(map[base.Key]bool)
+	for _, hole := range holes {
+		key, _ := base.GetKey(hole)
+		valid[key] = true
+		outcome, ok := outcomes[key]
+		if !ok {
+			result = append(result, fmt.Errorf("missing outcome for hole %v", hole))
+			continue
+		}
+
+		if math.Abs(outcome.Win+outcome.Tie+outcome.Lose-1) > verifyTolerance {
+			result = append(result, fmt.Errorf("outcome for hole %v has probabilities summing to %v, want 1", hole,
+				outcome.Win+outcome.Tie+outcome.Lose))
+		}
+		if outcome.Equity < outcome.Win-verifyTolerance || outcome.Equity > outcome.Win+outcome.Tie+verifyTolerance {
+			result = append(result, fmt.Errorf("outcome for hole %v has equity %v outside of win %v and win plus tie %v",
+				hole, outcome.Equity, outcome.Win, outcome.Win+outcome.Tie))
+		}
+
+		class := getHoleClass(hole)
+		if other, ok := classes[class]; !ok {
+			classes[class] = outcome
+		} else if other != outcome {
+			result = append(result, fmt.Errorf("outcome for hole %v is %v, but %v for another hole with the same ranks "+
+				"and suitedness", hole, outcome, other))
+		}
+	}
+	for key := range outcomes {
+		if !valid[key] {
+			result = append(result, fmt.Errorf("found outcome for invalid hole %#x", key))
+		}
+	}
+
+	return result
+}
+
+// enumerateInitialOutcomes computes the initial outcomes against a single opponent by enumerating every board.
+func enumerateInitialOutcomes() map[base.Key]Outcome {
 	deck := base.NewDeck()
 	boards := base.GetCombinations(deck.GetCards(), 5)
 
@@ -62,7 +214,7 @@ func PrecomputeInitialOutcomes() error {
 			for i := lower; i < upper; i++ {
 				deck := base.NewDeck()
 				deck.Remove(boards[i])
-				levels := evaluate(deck.GetCards(), boards[i])
+				levels := evaluate(deck.GetCards(), boards[i], base.TexasHoldem{})
 
 				// For every hole...
 				for _, holes := range levels {
@@ -104,21 +256,66 @@ func PrecomputeInitialOutcomes() error {
 			accumulationsTotal[k] = a
 		}
 	}
-	outcomes := make(map[base.Key]outcome)
+	outcomes := make(map[base.Key]Outcome)
 	for key, accumulation := range accumulationsTotal {
-		outcomes[key] = outcome{
-			float64(accumulation.worse) / float64(accumulation.total),
-			float64(accumulation.same) / float64(accumulation.total),
-			float64(accumulation.better) / float64(accumulation.total)}
+		outcomes[key] = getOutcome(float64(accumulation.better), float64(accumulation.same),
+			float64(accumulation.worse), 1)
 	}
-
-	return writeInitialOutcomes(outcomes)
+	return outcomes
 }
 
-type outcome struct {
-	win  float64
-	tie  float64
-	lose float64
+// simulateInitialOutcomes estimates the initial outcomes against the given number of opponents by simulating random
+// games. Holes that are identical up to a permutation of suits have identical outcomes, so only one hole from each
+// class is simulated. Each class is simulated from its own source seeded from the given seed, so the outcomes don't
+// depend on how the classes are split between threads.
+func simulateInitialOutcomes(opponents int, seed int64) map[base.Key]Outcome {
+	classes := make(map[holeClass][][]base.Card)
+	representatives := [][]base.Card{}
+	for _, hole := range base.GetCombinations(base.NewDeck().GetCards(), 2) {
+		class := getHoleClass(hole)
+		if _, ok := classes[class]; !ok {
+			representatives = append(representatives, hole)
+		}
+		classes[class] = append(classes[class], hole)
+	}
+
+	log.Printf("num hole classes %v", len(representatives))
+
+	n := runtime.NumCPU()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	outcomes := make(map[base.Key]Outcome)
+	for id := 0; id < n; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			lower := id * len(representatives) / n
+			upper := (id + 1) * len(representatives) / n
+			log.Printf("thread %v starting work on hole classes %v to %v", id, lower, upper)
+			for i := lower; i < upper; i++ {
+				r := rand.New(rand.NewSource(seed + int64(i)))
+				t := tally{}
+				simulate(representatives[i], []base.Card{}, base.GetBoardSize(base.TexasHoldem{}),
+					Options{Opponents: opponents}, initialOutcomeSamples, r, &t)
+				outcome := t.getEstimate().Outcome
+
+				mu.Lock()
+				for _, hole := range classes[getHoleClass(representatives[i])] {
+					key, _ := base.GetKey(hole)
+					outcomes[key] = outcome
+				}
+				mu.Unlock()
+
+				if ((i+1)-lower)%10 == 0 {
+					log.Printf("thread %v completed %v out of %v hole classes", id, (i+1)-lower, upper-lower)
+				}
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return outcomes
 }
 
 type accumulation struct {
@@ -128,19 +325,34 @@ type accumulation struct {
 	total  int64
 }
 
-func readInitialOutcomes() (map[base.Key]outcome, error) {
-	file, err := os.Open(initialOutcomeFile)
+// getInitialOutcomeTable returns the initial outcomes for the given number of opponents, parsing them only once.
+func getInitialOutcomeTable(opponents int) (map[base.Key]Outcome, error) {
+	t := &initialOutcomeTables[opponents-1]
+	t.once.Do(func() {
+		t.outcomes, t.err = readInitialOutcomes(opponents)
+	})
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.outcomes, t.err
+}
+
+func readInitialOutcomes(opponents int) (map[base.Key]Outcome, error) {
+	file, err := initialOutcomeFiles.Open(fmt.Sprintf(initialOutcomeFile, opponents))
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	outcomes := make(map[base.Key]outcome)
-	scanner := bufio.NewScanner(file)
+	return parseInitialOutcomes(file)
+}
+
+func parseInitialOutcomes(r io.Reader) (map[base.Key]Outcome, error) {
+	outcomes := make(map[base.Key]Outcome)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Fields(line)
-		if len(parts) != 4 {
+		if len(parts) != 5 {
 			return nil, fmt.Errorf("unable to parse line in outcome file: %v", line)
 		}
 		key, err := strconv.ParseUint(parts[0], 0, 64)
@@ -159,7 +371,14 @@ func readInitialOutcomes() (map[base.Key]outcome, error) {
 		if err != nil {
 			return nil, fmt.Errorf("unable to parse lose in outcome file: %v", parts[3])
 		}
-		outcomes[base.Key(key)] = outcome{win, tie, lose}
+		equity, err := strconv.ParseFloat(parts[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse equity in outcome file: %v", parts[4])
+		}
+		if _, ok := outcomes[base.Key(key)]; ok {
+			return nil, fmt.Errorf("duplicate key in outcome file: %v", parts[0])
+		}
+		outcomes[base.Key(key)] = Outcome{win, tie, lose, equity}
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
@@ -171,35 +390,28 @@ func readInitialOutcomes() (map[base.Key]outcome, error) {
 // count how many hands among hands can coexist with the hand, i.e. do not have overlapping cards
 func countCoexisting(hand []base.Card, hands [][]base.Card) int64 {
 	result := int64(0)
-loop:
+	cards := base.NewCardSet(hand...)
 	for _, h := range hands {
-		for _, x := range h {
-			for _, y := range hand {
-				if x == y {
-					continue loop
-				}
-			}
+		if !cards.Overlaps(base.NewCardSet(h...)) {
+			result++
 		}
-		result++
 	}
 	return result
 }
 
-func writeInitialOutcomes(outcomes map[base.Key]outcome) error {
-	var b bytes.Buffer
-	for key, outcome := range outcomes {
-		b.WriteString(fmt.Sprintf("%#x %v %v %v\n", key, outcome.win, outcome.tie, outcome.lose))
+func writeInitialOutcomes(w io.Writer, outcomes map[base.Key]Outcome) error {
+	keys := []base.Key{}
+	for key := range outcomes {
+		keys = append(keys, key)
 	}
+	sort.Slice(keys, func(x, y int) bool { return keys[x] < keys[y] })
 
-	file, err := os.Create(initialOutcomeFile)
-	if err != nil {
-		return err
+	var b bytes.Buffer
+	for _, key := range keys {
+		outcome := outcomes[key]
+		b.WriteString(fmt.Sprintf("%#x %v %v %v %v\n", key, outcome.Win, outcome.Tie, outcome.Lose, outcome.Equity))
 	}
-	defer file.Close()
 
-	_, err = file.Write(b.Bytes())
-	if err != nil {
-		return err
-	}
-	return file.Sync()
+	_, err := w.Write(b.Bytes())
+	return err
 }
@@ -0,0 +1,181 @@
+package prediction_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetInitialOutcomesDeadCards(t *testing.T) {
+	// The precomputed tables assume a full deck, so dead cards are sampled instead, reproducibly
+	hole := cards(t, "AsAh")
+	full, err := prediction.GetInitialOutcomes(hole, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() returned error: %v", err)
+	}
+	// A dead deuce barely matters, within 4 standard errors of the default samples
+	got, err := prediction.GetInitialOutcomes(hole, prediction.Options{Dead: cards(t, "2c")})
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() returned error: %v", err)
+	}
+	if math.Abs(got.Equity-full.Equity) > 0.005 || math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 {
+		t.Errorf("GetInitialOutcomes() with 2c dead = %+v, want close to %+v", got, full)
+	}
+	// A dead ace leaves only one card to make a set
+	options := prediction.Options{Dead: cards(t, "Ac")}
+	got, err = prediction.GetInitialOutcomes(hole, options)
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() returned error: %v", err)
+	}
+	if got.Equity > full.Equity-0.005 {
+		t.Errorf("GetInitialOutcomes() with Ac dead = %+v, want equity below %v", got, full.Equity)
+	}
+	again, err := prediction.GetInitialOutcomes(hole, options)
+	if err != nil || again != got {
+		t.Errorf("GetInitialOutcomes() with dead cards again = %+v, %v, want %+v", again, err, got)
+	}
+
+	advice, err := prediction.GetCallAdvice(hole, nil, prediction.Bet{Pot: 3, ToCall: 1, Stack: 100}, options)
+	if err != nil {
+		t.Fatalf("GetCallAdvice() with dead cards returned error: %v", err)
+	}
+	if advice.Outcome != got || advice.EV <= 0 {
+		t.Errorf("GetCallAdvice() with dead cards = %+v, want positive EV with outcome %+v", advice, got)
+	}
+}
+
+func TestGetInitialOutcomes(t *testing.T) {
+	tests := []struct {
+		name      string
+		hole      string
+		opponents int
+		equity    float64
+	}{
+		{"aces", "AsAh", 1, 0.852},
+		{"aces against 2", "AsAh", 2, 0.735},
+		{"aces against 9", "AsAh", 9, 0.314},
+		{"seven deuce", "7c2d", 1, 0.346},
+		{"suited connectors", "8h7h", 1, 0.479},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetInitialOutcomes(cards(t, tt.hole), prediction.Options{Opponents: tt.opponents})
+			if err != nil {
+				t.Fatalf("GetInitialOutcomes() error = %v", err)
+			}
+			if math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 {
+				t.Errorf("GetInitialOutcomes() = %+v, which doesn't sum to 1", got)
+			}
+			if math.Abs(got.Equity-tt.equity) > 0.01 {
+				t.Errorf("GetInitialOutcomes() = %+v, want equity %v", got, tt.equity)
+			}
+		})
+	}
+}
+
+func TestGetInitialOutcomesOpponents(t *testing.T) {
+	hole := cards(t, "KsQs")
+	previous := prediction.Outcome{Win: 1, Equity: 1}
+	for opponents := 1; opponents <= prediction.MaxOpponents; opponents++ {
+		got, err := prediction.GetInitialOutcomes(hole, prediction.Options{Opponents: opponents})
+		if err != nil {
+			t.Fatalf("GetInitialOutcomes() against %v error = %v", opponents, err)
+		}
+		if got.Equity >= previous.Equity {
+			t.Errorf("GetInitialOutcomes() against %v = %+v, want less than %+v", opponents, got, previous)
+		}
+		previous = got
+	}
+	too := prediction.Options{Opponents: prediction.MaxOpponents + 1}
+	if _, err := prediction.GetInitialOutcomes(hole, too); err == nil {
+		t.Errorf("GetInitialOutcomes() against %v succeeded, want error", prediction.MaxOpponents+1)
+	}
+}
+
+func TestVerifyInitialOutcomes(t *testing.T) {
+	for opponents := 1; opponents <= prediction.MaxOpponents; opponents++ {
+		path := fmt.Sprintf("data/initial_outcomes_%v", opponents)
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if problems := prediction.VerifyInitialOutcomes(f); len(problems) > 0 {
+			t.Errorf("VerifyInitialOutcomes(%v) = %v", path, problems)
+		}
+		f.Close()
+	}
+
+	if problems := prediction.VerifyInitialOutcomes(strings.NewReader("not a table\n")); len(problems) == 0 {
+		t.Errorf("VerifyInitialOutcomes() of a bad table found no problems")
+	}
+}
+
+func TestVerifyInitialOutcomesProblems(t *testing.T) {
+	data, err := os.ReadFile("data/initial_outcomes_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n")
+	fields := strings.Fields(lines[0])
+
+	tests := []struct {
+		name     string
+		table    string
+		problems int
+	}{
+		// A missing hole is reported both as missing and in the count of holes
+		{"missing", strings.Join(lines[1:], ""), 2},
+		// Outcomes that don't sum to 1 here also have an equity below the win, and differ from the rest of the class
+		{"sum", fmt.Sprintf("%v 1 1 1 %v\n", fields[0], fields[4]) + strings.Join(lines[1:], ""), 3},
+		{"invalid hole", string(data) + "0x11 0.5 0 0.5 0.5\n", 2},
+		{"unparsable", "0x11 0.5\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if problems := prediction.VerifyInitialOutcomes(strings.NewReader(tt.table)); len(problems) != tt.problems {
+				t.Errorf("VerifyInitialOutcomes() = %v, want %v problems", problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestLoadInitialOutcomes(t *testing.T) {
+	// Replace the table for 9 opponents with the one for 1, then put the embedded table back
+	opponents := prediction.MaxOpponents
+	defer func() {
+		if err := prediction.LoadInitialOutcomesFile(opponents, fmt.Sprintf("data/initial_outcomes_%v",
+			opponents)); err != nil {
+			t.Errorf("LoadInitialOutcomesFile() error = %v", err)
+		}
+	}()
+
+	hole := cards(t, "AsAh")
+	want, err := prediction.GetInitialOutcomes(hole, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() error = %v", err)
+	}
+	if err := prediction.LoadInitialOutcomesFile(opponents, "data/initial_outcomes_1"); err != nil {
+		t.Fatalf("LoadInitialOutcomesFile() error = %v", err)
+	}
+	got, err := prediction.GetInitialOutcomes(hole, prediction.Options{Opponents: opponents})
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("GetInitialOutcomes() = %+v from the loaded table, want %+v", got, want)
+	}
+
+	if err := prediction.LoadInitialOutcomes(0, strings.NewReader("")); err == nil {
+		t.Errorf("LoadInitialOutcomes() for 0 opponents succeeded, want error")
+	}
+	if err := prediction.LoadInitialOutcomes(opponents, strings.NewReader("0x11 0.5\n")); err == nil {
+		t.Errorf("LoadInitialOutcomes() of a bad table succeeded, want error")
+	}
+	if err := prediction.LoadInitialOutcomesFile(opponents, "data/missing"); err == nil {
+		t.Errorf("LoadInitialOutcomesFile() of a missing file succeeded, want error")
+	}
+}
@@ -0,0 +1,313 @@
+package prediction
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// ranks are all ranks from lowest to highest.
+var ranks = []base.Rank{base.Two, base.Three, base.Four, base.Five, base.Six, base.Seven, base.Eight, base.Nine,
+	base.Ten, base.Jack, base.Queen, base.King, base.Ace}
+
+// suits are all suits.
+var suits = []base.Suit{base.Club, base.Diamond, base.Heart, base.Spade}
+
+// ParseRange parses a range in standard notation, e.g. "QQ+, AKs, AJo-ATo, 76s, KcQh", into a range of concrete holes
+// with a weight of 1 each. Holes that overlap with the board or dead cards are removed. The notation is a comma
+// separated list of:
+//   - pairs, e.g. "77", "77+" for 77 and better, or "99-66"
+//   - suited or offsuit holes, e.g. "AKs", "AKo", or "AK" for both, "ATs+" for ATs up to AKs, or "AJo-ATo"
+//   - specific holes, e.g. "KcQh"
+func ParseRange(notation string, board []base.Card, dead []base.Card) (Range, error) {
+	result := NewRange()
+	for _, token := range strings.Split(notation, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		holes, err := parseRangeToken(token)
+		if err != nil {
+			return nil, err
+		}
+		for _, hole := range holes {
+			if overlaps(hole, board) || overlaps(hole, dead) {
+				continue
+			}
+			result.Add(hole, 1)
+		}
+	}
+	return result, nil
+}
+
+// parseRangeToken parses a single token of range notation into its holes.
+func parseRangeToken(token string) ([][]base.Card, error) {
+	// Classes of holes are at most 3 characters without a + or -, so anything longer must be a specific hole
+	if len(token) > 3 && !strings.ContainsAny(token, "+-") {
+		hole, err := base.ParseCards(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hole %q in range: %v", token, err)
+		}
+		if len(hole) != 2 {
+			return nil, fmt.Errorf("invalid hole %q in range: expected 2 cards", token)
+		}
+		return [][]base.Card{hole}, nil
+	}
+
+	if strings.HasSuffix(token, "+") {
+		start, err := parseNotationClass(strings.TrimSuffix(token, "+"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q in range: %v", token, err)
+		}
+		// For pairs, increase the pair up to aces, otherwise increase the low card up to just below the high card
+		end := start
+		if start.high == start.low {
+			end.high, end.low = base.Ace, base.Ace
+		} else {
+			end.low = start.high - 1
+		}
+		return getNotationClassHoles(start, end), nil
+	}
+
+	if parts := strings.Split(token, "-"); len(parts) == 2 {
+		end, err := parseNotationClass(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q in range: %v", token, err)
+		}
+		start, err := parseNotationClass(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid token %q in range: %v", token, err)
+		}
+		if (start.high == start.low) != (end.high == end.low) || start.suitedness != end.suitedness ||
+			(start.high != start.low && start.high != end.high) {
+			return nil, fmt.Errorf("invalid token %q in range: both ends must be pairs, or share a high card and suitedness",
+				token)
+		}
+		if start.low > end.low {
+			start, end = end, start
+		}
+		return getNotationClassHoles(start, end), nil
+	}
+
+	class, err := parseNotationClass(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token %q in range: %v", token, err)
+	}
+	return getNotationClassHoles(class, class), nil
+}
+
+// suitedness is whether a class in range notation covers suited holes, offsuit holes, or both.
+type suitedness int
+
+const (
+	anySuited suitedness = iota
+	suited
+	offsuit
+)
+
+// notationClass is a class of holes in range notation, e.g. "AKs", "AK", or "77".
+type notationClass struct {
+	high       base.Rank
+	low        base.Rank
+	suitedness suitedness
+}
+
+func parseNotationClass(s string) (notationClass, error) {
+	if len(s) != 2 && len(s) != 3 {
+		return notationClass{}, fmt.Errorf("expected 2 ranks and an optional s or o")
+	}
+	high, err := base.ParseRank(rune(s[0]))
+	if err != nil {
+		return notationClass{}, err
+	}
+	low, err := base.ParseRank(rune(s[1]))
+	if err != nil {
+		return notationClass{}, err
+	}
+	if high < low {
+		high, low = low, high
+	}
+	class := notationClass{high, low, anySuited}
+	if len(s) == 3 {
+		switch s[2] {
+		case 's', 'S':
+			class.suitedness = suited
+		case 'o', 'O':
+			class.suitedness = offsuit
+		default:
+			return notationClass{}, fmt.Errorf("expected s or o, got %q", s[2])
+		}
+		if high == low {
+			return notationClass{}, fmt.Errorf("pairs cannot be suited or offsuit")
+		}
+	}
+	return class, nil
+}
+
+// getNotationClassHoles returns all holes in classes from start to end, which must differ only in their low card, or
+// must both be pairs.
+func getNotationClassHoles(start, end notationClass) [][]base.Card {
+	result := [][]base.Card{}
+	for low := start.low; low <= end.low; low++ {
+		high := start.high
+		if start.high == start.low {
+			high = low
+		}
+		for _, x := range suits {
+			for _, y := range suits {
+				if high == low && x >= y {
+					continue
+				}
+				if (x == y && start.suitedness == offsuit) || (x != y && start.suitedness == suited) {
+					continue
+				}
+				result = append(result, []base.Card{base.NewCard(high, x), base.NewCard(low, y)})
+			}
+		}
+	}
+	return result
+}
+
+// FormatRange formats a range in the shortest standard notation accepted by ParseRange, ignoring weights. Holes that
+// overlap with the board or dead cards are assumed to have been removed from the range, so that a class of holes such
+// as "AKs" is still written as a class when some of its holes are blocked.
+func FormatRange(r Range, board []base.Card, dead []base.Card) string {
+	known := append(append([]base.Card{}, board...), dead...)
+	tokens := []string{}
+	partial := []notationClass{}
+
+	// Every class is either complete, i.e. contains every unblocked hole, or must be written hole by hole
+	getStatus := func(class notationClass) classStatus {
+		holes := getNotationClassHoles(class, class)
+		present, blocked := 0, 0
+		for _, hole := range holes {
+			if r.GetWeight(hole) > 0 {
+				present++
+			} else if overlaps(hole, known) {
+				blocked++
+			}
+		}
+		if blocked == len(holes) {
+			return blockedClass
+		} else if present+blocked == len(holes) {
+			return completeClass
+		} else if present == 0 {
+			return emptyClass
+		}
+		partial = append(partial, class)
+		return partialClass
+	}
+
+	// Pairs, from highest to lowest
+	statuses := make([]classStatus, len(ranks))
+	for i, rank := range ranks {
+		statuses[i] = getStatus(notationClass{rank, rank, anySuited})
+	}
+	for _, run := range getRuns(statuses) {
+		high, low := ranks[run.high].String(), ranks[run.low].String()
+		tokens = append(tokens, formatRun(high+high, low+low, run, len(ranks)-1))
+	}
+
+	// Other holes, from highest to lowest high card
+	for h := len(ranks) - 1; h > 0; h-- {
+		high := ranks[h].String()
+		suitedStatuses, offsuitStatuses := make([]classStatus, h), make([]classStatus, h)
+		for l := 0; l < h; l++ {
+			suitedStatuses[l] = getStatus(notationClass{ranks[h], ranks[l], suited})
+			offsuitStatuses[l] = getStatus(notationClass{ranks[h], ranks[l], offsuit})
+		}
+		suitedRuns, offsuitRuns := getRuns(suitedStatuses), getRuns(offsuitStatuses)
+
+		// Runs that are identical for suited and offsuit holes can be written once for both
+		both := make(map[run]bool)
+		for _, s := range suitedRuns {
+			for _, o := range offsuitRuns {
+				if s == o {
+					both[s] = true
+				}
+			}
+		}
+		for _, run := range suitedRuns {
+			suffix := "s"
+			if both[run] {
+				suffix = ""
+			}
+			tokens = append(tokens, formatRun(high+ranks[run.high].String()+suffix,
+				high+ranks[run.low].String()+suffix, run, h-1))
+		}
+		for _, run := range offsuitRuns {
+			if !both[run] {
+				tokens = append(tokens, formatRun(high+ranks[run.high].String()+"o",
+					high+ranks[run.low].String()+"o", run, h-1))
+			}
+		}
+	}
+
+	// Finally, holes from incomplete classes
+	for _, class := range partial {
+		for _, hole := range getNotationClassHoles(class, class) {
+			if r.GetWeight(hole) > 0 {
+				tokens = append(tokens, hole[0].String()+hole[1].String())
+			}
+		}
+	}
+
+	return strings.Join(tokens, ", ")
+}
+
+// classStatus is how much of a class of holes is in a range.
+type classStatus int
+
+const (
+	emptyClass classStatus = iota
+	partialClass
+	completeClass
+	blockedClass // every hole in the class overlaps with known cards
+)
+
+// run is a run of consecutive complete classes, by index.
+type run struct {
+	high int
+	low  int
+}
+
+// getRuns returns the runs of complete classes, from highest to lowest. Blocked classes can join complete classes into
+// a single run, and can extend a run up to the highest class, but cannot otherwise start or end a run.
+func getRuns(statuses []classStatus) []run {
+	result := []run{}
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i] != completeClass && statuses[i] != blockedClass {
+			continue
+		}
+		j := i
+		for j > 0 && (statuses[j-1] == completeClass || statuses[j-1] == blockedClass) {
+			j--
+		}
+		r := run{i, j}
+		i = j
+		for r.low <= r.high && statuses[r.low] == blockedClass {
+			r.low++
+		}
+		if r.high != len(statuses)-1 {
+			for r.high >= r.low && statuses[r.high] == blockedClass {
+				r.high--
+			}
+		}
+		if r.low > r.high || (r.high == r.low && statuses[r.high] == blockedClass) {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// formatRun formats a run given the notation of its highest and lowest classes, and the index of the highest class.
+func formatRun(high, low string, r run, top int) string {
+	if r.high == r.low {
+		return high
+	} else if r.high == top {
+		return low + "+"
+	}
+	return high + "-" + low
+}
@@ -0,0 +1,110 @@
+package prediction_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func cards(t *testing.T, s string) []base.Card {
+	t.Helper()
+	result, err := base.ParseCards(s)
+	if err != nil {
+		t.Fatalf("ParseCards(%q) error = %v", s, err)
+	}
+	return result
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		board    string
+		dead     string
+		size     int
+		contains []string
+		excludes []string
+	}{
+		{"pairs", "QQ+", "", "", 18, []string{"QcQd", "AhAs"}, []string{"JcJd"}},
+		{"pair range", "99-66", "", "", 24, []string{"9c9d", "6h6s"}, []string{"TcTd", "5c5d"}},
+		{"suited", "AKs", "", "", 4, []string{"AcKc"}, []string{"AcKd"}},
+		{"offsuit range", "AJo-ATo", "", "", 24, []string{"AcJd", "AsTh"}, []string{"AcJc", "AcQd"}},
+		{"suited plus", "ATs+", "", "", 16, []string{"AcTc", "AsKs"}, []string{"Ac9c"}},
+		{"both", "76", "", "", 16, []string{"7c6c", "7c6d"}, nil},
+		{"specific", "KcQh", "", "", 1, []string{"KcQh"}, []string{"KhQc"}},
+		{"mixed", "QQ+, AKs, AJo-ATo, KcQh", "", "", 18 + 4 + 24 + 1, []string{"KcQh", "AdKd", "AcTd"}, nil},
+		{"board", "AA", "As2c3d", "", 3, []string{"AcAd"}, []string{"AcAs"}},
+		{"dead", "AKs", "", "Kh", 3, []string{"AsKs"}, []string{"AhKh"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := prediction.ParseRange(tt.notation, cards(t, tt.board), cards(t, tt.dead))
+			if err != nil {
+				t.Fatalf("ParseRange(%q) error = %v", tt.notation, err)
+			}
+			if got := len(r.GetHoles()); got != tt.size {
+				t.Errorf("ParseRange(%q) has %v holes, want %v", tt.notation, got, tt.size)
+			}
+			for _, hole := range tt.contains {
+				if r.GetWeight(cards(t, hole)) != 1 {
+					t.Errorf("ParseRange(%q) doesn't contain %v", tt.notation, hole)
+				}
+			}
+			for _, hole := range tt.excludes {
+				if r.GetWeight(cards(t, hole)) != 0 {
+					t.Errorf("ParseRange(%q) contains %v", tt.notation, hole)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for _, notation := range []string{"A", "AKx", "AAs", "XK", "AK-QJ", "99-AKs", "AKs-AKo", "KcQ", "KcQhJd", "AcAc",
+		"QQ++"} {
+		if _, err := prediction.ParseRange(notation, nil, nil); err == nil {
+			t.Errorf("ParseRange(%q) succeeded, want error", notation)
+		}
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		notation string
+		board    string
+		want     string
+	}{
+		{"pairs", "QQ+", "", "QQ+"},
+		{"pair range", "66-99", "", "99-66"},
+		{"suited and offsuit", "AKs, AKo", "", "AK"},
+		{"runs", "ATs+, KQo, KJo", "", "ATs+, KJo+"},
+		{"mixed", "QQ+, AKs, AJo-ATo, KcQh", "", "QQ+, AKs, AJo-ATo, KcQh"},
+		{"blocked", "AA, KK", "Ac2d3h", "KK+"},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := cards(t, tt.board)
+			r, err := prediction.ParseRange(tt.notation, board, nil)
+			if err != nil {
+				t.Fatalf("ParseRange(%q) error = %v", tt.notation, err)
+			}
+			got := prediction.FormatRange(r, board, nil)
+			if got != tt.want {
+				t.Errorf("FormatRange() = %q, want %q", got, tt.want)
+			}
+
+			// Whatever is formatted must parse back to the same range
+			again, err := prediction.ParseRange(got, board, nil)
+			if err != nil {
+				t.Fatalf("ParseRange(%q) error = %v", got, err)
+			}
+			if !reflect.DeepEqual(again.GetHoles(), r.GetHoles()) {
+				t.Errorf("ParseRange(%q) = %v, want %v", got, again.GetHoles(), r.GetHoles())
+			}
+		})
+	}
+}
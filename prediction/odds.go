@@ -0,0 +1,158 @@
+package prediction
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// Recommendation is the recommended response to a bet.
+type Recommendation int
+
+const (
+	Fold  Recommendation = iota + 1 // Calling loses chips on average
+	Check                           // There is nothing to call
+	Call                            // Calling wins or breaks even on average
+)
+
+func (r Recommendation) String() string {
+	switch r {
+	case Fold:
+		return "Fold"
+	case Check:
+		return "Check"
+	case Call:
+		return "Call"
+	}
+	return "(invalid)"
+}
+
+// MarshalText writes the recommendation as its name.
+func (r Recommendation) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Bet is the bet that a player is facing, in chips.
+type Bet struct {
+	// Pot is the size of the pot, including every bet made so far on the current street and the bet being faced.
+	Pot int64 `json:"pot"`
+	// ToCall is the number of chips needed to call the bet, or 0 if there is no bet.
+	ToCall int64 `json:"to_call"`
+	// Stack is the effective stack, the smaller of the chips left behind by the player and by the largest stack among
+	// the opponents still in the hand, before calling.
+	Stack int64 `json:"stack"`
+}
+
+// CallAdvice is the arithmetic of calling a bet rather than folding, given the equity of the hole.
+type CallAdvice struct {
+	// Outcome is the outcome of the hole against all opponents at the end of the game.
+	Outcome Outcome `json:"outcome"`
+	// Call is the number of chips put in to call, which is less than the bet if the effective stack cannot cover it.
+	Call int64 `json:"call"`
+	// AllIn is whether calling puts the effective stack all in.
+	AllIn bool `json:"all_in"`
+	// Pot is the size of the pot after calling, which is what the call wins. Any part of the bet that the effective
+	// stack cannot cover is returned to the bettor and left out.
+	Pot int64 `json:"pot"`
+	// PotOdds is the ratio of the pot before calling to the call, e.g. 3 for 3 to 1, or 0 if there is no bet.
+	PotOdds float64 `json:"pot_odds"`
+	// RequiredEquity is the equity needed for calling to break even, Call / Pot.
+	RequiredEquity float64 `json:"required_equity"`
+	// EV is the expected number of chips won by calling rather than folding, Equity * Pot - Call, ignoring any later
+	// betting. It is 0 if there is no bet, since checking puts nothing in that folding would save.
+	EV float64 `json:"ev"`
+	// Implied is the number of chips that would have to be won from the effective stack later in the hand, when the
+	// hole wins, for a losing call to break even. It is 0 if calling doesn't lose chips or nothing is left behind.
+	Implied float64 `json:"implied,omitempty"`
+	// Recommendation is what to do about the bet.
+	Recommendation Recommendation `json:"recommendation"`
+	// Reason explains the recommendation.
+	Reason string `json:"reason"`
+}
+
+// GetCallAdvice returns, given a hole and a board dealt up to some street of the variant, the advice for calling a bet
+// with the outcome from GetInitialOutcomes, or GetFutureOutcomes once the board has been dealt, against the number of
+// opponents in the options. Outcomes that those cannot compute, e.g. initial outcomes in Omaha, can be estimated and
+// passed to AdviseCall instead.
+func GetCallAdvice(hole []base.Card, board []base.Card, bet Bet, options Options) (CallAdvice, error) {
+	if err := bet.check(); err != nil {
+		return CallAdvice{}, err
+	}
+	var outcome Outcome
+	var err error
+	if len(board) == 0 {
+		outcome, err = GetInitialOutcomes(hole, options)
+	} else {
+		outcome, err = GetFutureOutcomes(hole, board, options)
+	}
+	if err != nil {
+		return CallAdvice{}, err
+	}
+	return AdviseCall(outcome, bet)
+}
+
+// AdviseCall returns the advice for calling a bet given the outcome of the hole, recommending a call whenever it wins
+// or breaks even on average. Only the bet being faced is considered, so the advice doesn't account for being raised
+// after calling or for winning more on later streets, which Implied estimates.
+func AdviseCall(outcome Outcome, bet Bet) (CallAdvice, error) {
+	if err := bet.check(); err != nil {
+		return CallAdvice{}, err
+	}
+
+	advice := CallAdvice{Outcome: outcome, Call: bet.ToCall, Pot: bet.Pot}
+	if bet.ToCall == 0 {
+		advice.Recommendation = Check
+		advice.Reason = fmt.Sprintf("there is nothing to call, and the hole has %.1f%% equity in the pot of %v",
+			100*outcome.Equity, bet.Pot)
+		return advice, nil
+	}
+
+	if bet.ToCall >= bet.Stack {
+		// The part of the bet that can't be covered goes back to the bettor
+		advice.Call, advice.AllIn = bet.Stack, true
+		advice.Pot -= bet.ToCall - bet.Stack
+	}
+	advice.PotOdds = float64(advice.Pot) / float64(advice.Call)
+	advice.Pot += advice.Call
+	advice.RequiredEquity = float64(advice.Call) / float64(advice.Pot)
+	advice.EV = outcome.Equity*float64(advice.Pot) - float64(advice.Call)
+
+	allIn := ""
+	if advice.AllIn {
+		allIn = " all in"
+	}
+	reason := fmt.Sprintf("calling %v%v into a pot of %v needs %.1f%% equity, and the hole has %.1f%%", advice.Call,
+		allIn, advice.Pot-advice.Call, 100*advice.RequiredEquity, 100*outcome.Equity)
+	if advice.EV >= 0 {
+		advice.Recommendation = Call
+		advice.Reason = fmt.Sprintf("%v, so calling wins %.2f chips on average", reason, advice.EV)
+		return advice, nil
+	}
+
+	advice.Recommendation = Fold
+	advice.Reason = fmt.Sprintf("%v, so calling loses %.2f chips on average", reason, -advice.EV)
+	behind := bet.Stack - advice.Call
+	if behind > 0 && outcome.Equity > 0 {
+		// Winning X more when the hole wins breaks even when Equity * (Pot + X) = Call
+		advice.Implied = float64(advice.Call)/outcome.Equity - float64(advice.Pot)
+		if advice.Implied <= float64(behind) {
+			advice.Reason = fmt.Sprintf("%v, unless %.0f more of the %v chips behind are won when the hole wins",
+				advice.Reason, advice.Implied, behind)
+		}
+	}
+	return advice, nil
+}
+
+// check returns an error if the bet is impossible.
+func (b Bet) check() error {
+	if b.Pot < 0 || b.ToCall < 0 || b.Stack < 0 {
+		return fmt.Errorf("pot, amount to call, and effective stack cannot be negative")
+	}
+	if b.ToCall > b.Pot {
+		return fmt.Errorf("pot of %v must include the bet of %v being called", b.Pot, b.ToCall)
+	}
+	if b.ToCall > 0 && b.Stack == 0 {
+		return fmt.Errorf("a bet of %v cannot be called with an effective stack of 0", b.ToCall)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package prediction_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestAdviseCall(t *testing.T) {
+	tests := []struct {
+		name           string
+		equity         float64
+		bet            prediction.Bet
+		call           int64
+		allIn          bool
+		pot            int64
+		potOdds        float64
+		requiredEquity float64
+		ev             float64
+		implied        float64
+		recommendation prediction.Recommendation
+	}{
+		{"no bet", 0.5, prediction.Bet{Pot: 20, Stack: 100}, 0, false, 20, 0, 0, 0, 0, prediction.Check},
+		// 10 into 30 gets 3 to 1, so 25% equity breaks even
+		{"break even", 0.25, prediction.Bet{Pot: 30, ToCall: 10, Stack: 100}, 10, false, 40, 3, 0.25, 0, 0,
+			prediction.Call},
+		{"call", 0.5, prediction.Bet{Pot: 30, ToCall: 10, Stack: 100}, 10, false, 40, 3, 0.25, 10, 0,
+			prediction.Call},
+		// Losing 2 on average needs 0.2 * (40 + X) = 10, so 10 more when the hole wins
+		{"fold", 0.2, prediction.Bet{Pot: 30, ToCall: 10, Stack: 100}, 10, false, 40, 3, 0.25, -2, 10,
+			prediction.Fold},
+		{"fold all in", 0.2, prediction.Bet{Pot: 30, ToCall: 10, Stack: 10}, 10, true, 40, 3, 0.25, -2, 0,
+			prediction.Fold},
+		// Only 20 of the bet of 50 can be called, so the other 30 goes back to the bettor
+		{"all in for less", 0.3, prediction.Bet{Pot: 80, ToCall: 50, Stack: 20}, 20, true, 70, 2.5, 2.0 / 7, 1,
+			0, prediction.Call},
+		{"no equity", 0, prediction.Bet{Pot: 30, ToCall: 10, Stack: 100}, 10, false, 40, 3, 0.25, -10, 0,
+			prediction.Fold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.AdviseCall(prediction.Outcome{Equity: tt.equity}, tt.bet)
+			if err != nil {
+				t.Fatalf("AdviseCall() error = %v", err)
+			}
+			if got.Call != tt.call || got.AllIn != tt.allIn || got.Pot != tt.pot ||
+				math.Abs(got.PotOdds-tt.potOdds) > 1e-9 || math.Abs(got.RequiredEquity-tt.requiredEquity) > 1e-9 ||
+				math.Abs(got.EV-tt.ev) > 1e-9 || math.Abs(got.Implied-tt.implied) > 1e-9 ||
+				got.Recommendation != tt.recommendation {
+				t.Errorf("AdviseCall() = %+v", got)
+			}
+			if got.Reason == "" {
+				t.Errorf("AdviseCall() has no reason")
+			}
+		})
+	}
+}
+
+func TestAdviseCallErrors(t *testing.T) {
+	for _, bet := range []prediction.Bet{
+		{Pot: -1, ToCall: 0, Stack: 10},
+		{Pot: 10, ToCall: -1, Stack: 10},
+		{Pot: 10, ToCall: 5, Stack: -1},
+		{Pot: 10, ToCall: 20, Stack: 100},
+		{Pot: 10, ToCall: 5, Stack: 0},
+	} {
+		if _, err := prediction.AdviseCall(prediction.Outcome{Equity: 0.5}, bet); err == nil {
+			t.Errorf("AdviseCall(%+v) succeeded, want error", bet)
+		}
+	}
+	if _, err := prediction.AdviseCall(prediction.Outcome{Equity: 0.5}, prediction.Bet{Pot: 10, Stack: 0}); err != nil {
+		t.Errorf("AdviseCall() with nothing to call and nothing behind error = %v", err)
+	}
+}
+
+func TestGetCallAdvice(t *testing.T) {
+	// Aces are 85% to win against a random hole, so they call anything
+	got, err := prediction.GetCallAdvice(cards(t, "AsAh"), nil, prediction.Bet{Pot: 200, ToCall: 100, Stack: 100},
+		prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetCallAdvice() error = %v", err)
+	}
+	if got.Recommendation != prediction.Call || math.Abs(got.Outcome.Equity-0.852) > 0.005 {
+		t.Errorf("GetCallAdvice() = %+v, want a call with 85.2%% equity", got)
+	}
+
+	// Both holes play the royal flush on the board, so calling always wins back half of the pot
+	got, err = prediction.GetCallAdvice(cards(t, "7c2d"), cards(t, "AsKsQsJsTs"),
+		prediction.Bet{Pot: 20, ToCall: 10, Stack: 100}, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetCallAdvice() error = %v", err)
+	}
+	if got.Recommendation != prediction.Call || got.Outcome.Tie != 1 {
+		t.Errorf("GetCallAdvice() = %+v, want a call splitting the royal flush on the board", got)
+	}
+}
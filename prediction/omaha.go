@@ -0,0 +1,38 @@
+package prediction
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// GetOmahaCurrentOrder is like GetCurrentOrder, but for Omaha, where the hole has 4 or 5 cards, every other possible
+// hole has as many cards as it, and every hand must be made of exactly 2 cards from the hole and 3 from the board.
+func GetOmahaCurrentOrder(hole []base.Card, board []base.Card, options Options) ([][][]base.Card, int64, int64, int64,
+	int64, Outcome, error) {
+	if len(hole) != 4 && len(hole) != 5 {
+		return nil, 0, 0, 0, 0, Outcome{}, fmt.Errorf("current order can only be returned for Omaha holes of 4 or 5 cards")
+	}
+	options.Variant = getOmahaVariant(hole)
+	return GetCurrentOrder(hole, board, options)
+}
+
+// GetOmahaFutureOutcomes is like GetFutureOutcomes, but for Omaha, where the hole has 4 or 5 cards, every opponent
+// holds as many cards as it, and every hand must be made of exactly 2 cards from the hole and 3 from the board. Every
+// subsequent board and opponent hole is enumerated, which is quick on the turn and river but can take minutes on the
+// flop; EstimateOutcomes with an Omaha variant samples games instead.
+func GetOmahaFutureOutcomes(hole []base.Card, board []base.Card, options Options) (Outcome, error) {
+	if len(hole) != 4 && len(hole) != 5 {
+		return Outcome{}, fmt.Errorf("future outcomes can only be predicted for Omaha holes of 4 or 5 cards")
+	}
+	options.Variant = getOmahaVariant(hole)
+	return GetFutureOutcomes(hole, board, options)
+}
+
+// getOmahaVariant returns the variant of Omaha played with holes of the same size as the given hole.
+func getOmahaVariant(hole []base.Card) base.Variant {
+	if len(hole) == 5 {
+		return base.FiveCardOmaha{}
+	}
+	return base.Omaha{}
+}
@@ -0,0 +1,74 @@
+package prediction_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetOmahaFutureOutcomes(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+		win   float64
+		lose  float64
+	}{
+		// A royal flush can't be beaten or tied
+		{"royal flush", "AsKs2c3d", "QsJsTs4h5h", 1, 0},
+		// Only one card of the hole can be used with the flush on the board, so this is just ace high and almost always
+		// loses
+		{"one flush card", "Ah2c3d4s", "KhQhJh9h7c", 0.02, 0.98},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetOmahaFutureOutcomes(cards(t, tt.hole), cards(t, tt.board), prediction.Options{})
+			if err != nil {
+				t.Fatalf("GetOmahaFutureOutcomes() error = %v", err)
+			}
+			if math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 {
+				t.Errorf("GetOmahaFutureOutcomes() = %+v, which doesn't sum to 1", got)
+			}
+			if math.Abs(got.Win-tt.win) > 0.01 || math.Abs(got.Lose-tt.lose) > 0.01 {
+				t.Errorf("GetOmahaFutureOutcomes() = %+v, want win %v and lose %v", got, tt.win, tt.lose)
+			}
+		})
+	}
+}
+
+func TestGetOmahaFutureOutcomesAgreesWithEstimate(t *testing.T) {
+	hole, board := cards(t, "AsAhKdQd"), cards(t, "2c7dKh9s3h")
+	exact, err := prediction.GetOmahaFutureOutcomes(hole, board, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetOmahaFutureOutcomes() error = %v", err)
+	}
+	_, _, _, _, _, current, err := prediction.GetOmahaCurrentOrder(hole, board, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetOmahaCurrentOrder() error = %v", err)
+	}
+	if math.Abs(current.Equity-exact.Equity) > 1e-9 {
+		t.Errorf("GetOmahaCurrentOrder() = %+v on the river, want %+v", current, exact)
+	}
+
+	options := prediction.Options{Variant: base.Omaha{}}
+	estimate, err := prediction.EstimateOutcomes(hole, board, options,
+		prediction.Sampling{Samples: 20000, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatalf("EstimateOutcomes() error = %v", err)
+	}
+	if exact.Equity < estimate.EquityInterval.Low || exact.Equity > estimate.EquityInterval.High {
+		t.Errorf("GetOmahaFutureOutcomes() = %+v, outside of EstimateOutcomes() = %+v", exact, estimate)
+	}
+}
+
+func TestGetOmahaFutureOutcomesErrors(t *testing.T) {
+	for _, hole := range []string{"AsKs", "AsKsQs", "AsKsQsJsTs9s"} {
+		if _, err := prediction.GetOmahaFutureOutcomes(cards(t, hole), cards(t, "2c3d4h"),
+			prediction.Options{}); err == nil {
+			t.Errorf("GetOmahaFutureOutcomes(%v) succeeded, want error", hole)
+		}
+	}
+}
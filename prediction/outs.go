@@ -0,0 +1,227 @@
+package prediction
+
+import (
+	"fmt"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// Draw is a kind of draw to a straight or flush.
+type Draw int
+
+const (
+	FlushDraw            Draw = iota + 1 // 4 cards of a suit, needing 1 more
+	OpenEndedDraw                        // 4 cards to a straight that 2 ranks complete, including double gutshots
+	Gutshot                              // 4 cards to a straight that only 1 rank completes
+	BackdoorFlushDraw                    // 3 cards of a suit on the flop, needing both the turn and river
+	BackdoorStraightDraw                 // 3 cards to a straight on the flop, needing both the turn and river
+)
+
+func (d Draw) String() string {
+	switch d {
+	case FlushDraw:
+		return "Flush draw"
+	case OpenEndedDraw:
+		return "Open-ended straight draw"
+	case Gutshot:
+		return "Gutshot"
+	case BackdoorFlushDraw:
+		return "Backdoor flush draw"
+	case BackdoorStraightDraw:
+		return "Backdoor straight draw"
+	}
+	return "(invalid)"
+}
+
+// MarshalText writes the draw as its description.
+func (d Draw) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Outs is an analysis of how a hole can improve on the next street.
+type Outs struct {
+	// Current is the current outcome against all opponents, assuming no other cards are dealt to the board.
+	Current Outcome `json:"current"`
+	// Cards are the next cards that would take the hole from behind to ahead, i.e. from an equity below 1/2 to an
+	// equity of at least 1/2 against all opponents. There are none if the hole is already ahead.
+	Cards []base.Card `json:"cards"`
+	// Draws are the straight and flush draws that the hole contributes to.
+	Draws []Draw `json:"draws"`
+	// NextCard is the probability that the next card is an out.
+	NextCard float64 `json:"next_card"`
+	// ByRiver is the probability that at least one out is dealt by the river, which is the same as NextCard on the
+	// turn.
+	ByRiver float64 `json:"by_river"`
+}
+
+// GetOuts returns, given a hole and a board of 3 or 4 cards in Texas Hold'em, the outs that would take the hole from
+// behind to ahead on the next card, the draws that it has, and the probability of hitting an out by each street. Each
+// opponent holds a hole from the given range, or a random hole if the range is nil. Outs are counted among every card
+// that has not been seen, since the opponent holes are unknown. Against more than one opponent, equities treat their
+// holes as independent, which is an approximation that keeps checking every card quick.
+func GetOuts(hole []base.Card, board []base.Card, opponent Range, options Options) (Outs, error) {
+	if _, ok := options.getVariant().(base.TexasHoldem); !ok {
+		return Outs{}, fmt.Errorf("outs can only be analyzed for Texas Hold'em")
+	}
+	if len(hole) != 2 {
+		return Outs{}, fmt.Errorf("outs can only be analyzed for holes with 2 cards")
+	}
+	if len(board) != 3 && len(board) != 4 {
+		return Outs{}, fmt.Errorf("outs can only be analyzed for boards with 3 or 4 cards")
+	}
+	if err := options.checkDead(hole, board); err != nil {
+		return Outs{}, err
+	}
+
+	deck := base.NewDeck()
+	deck.Remove(hole)
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	unseen := deck.GetCards()
+
+	var combos []combo
+	if opponent == nil {
+		for _, h := range base.GetCombinations(unseen, 2) {
+			combos = append(combos, combo{h, base.NewCardSet(h...), 1})
+		}
+	} else {
+		combos = opponent.getCombos(base.NewCardSet(hole...).Union(base.NewCardSet(board...)).Union(
+			base.NewCardSet(options.Dead...)))
+	}
+	if len(combos) == 0 {
+		return Outs{}, fmt.Errorf("opponent range has no holes that can coexist with the hole and board")
+	}
+
+	holeCards, boardCards := base.NewCardSet(hole...), base.NewCardSet(board...)
+	opponents := options.getOpponents()
+	result := Outs{
+		Current: getCombosOutcome(holeCards, boardCards, combos, opponents),
+		Cards:   []base.Card{},
+		Draws:   getDraws(hole, board),
+	}
+
+	if result.Current.Equity < 0.5 {
+		for _, c := range unseen {
+			next := getCombosOutcome(holeCards, boardCards.Union(base.NewCardSet(c)), combos, opponents)
+			if next.Equity >= 0.5 {
+				result.Cards = append(result.Cards, c)
+			}
+		}
+	}
+
+	// Every unseen card is equally likely to come next, and on the flop, we miss by the river only if we miss twice
+	outs, total := float64(len(result.Cards)), float64(len(unseen))
+	result.NextCard = outs / total
+	result.ByRiver = result.NextCard
+	if len(board) == 3 {
+		result.ByRiver = 1 - (total-outs)/total*(total-outs-1)/(total-1)
+	}
+	return result, nil
+}
+
+// getCombosOutcome returns the current outcome of the hole against all opponents, each holding one of the combos that
+// do not overlap with the hole or board.
+func getCombosOutcome(hole, board base.CardSet, combos []combo, opponents int) Outcome {
+	known := hole.Union(board)
+	score, _ := base.GetCardSetScore(known)
+	better, same, worse := 0.0, 0.0, 0.0
+	for _, c := range combos {
+		if c.cards.Overlaps(known) {
+			continue
+		}
+		opponentScore, _ := base.GetCardSetScore(board.Union(c.cards))
+		if opponentScore > score {
+			better += c.weight
+		} else if opponentScore == score {
+			same += c.weight
+		} else {
+			worse += c.weight
+		}
+	}
+	return getOutcome(better, same, worse, opponents)
+}
+
+// getDraws returns the straight and flush draws that the hole contributes to, i.e. that the board does not have by
+// itself. Backdoor draws are only returned on the flop, and only when there is no better draw of the same kind.
+func getDraws(hole, board []base.Card) []Draw {
+	result := []Draw{}
+	ours, boardOnly := getRankMask(hole, board), getRankMask(nil, board)
+
+	// Flushes need at least one card of the suit from the hole
+	var suitCounts, holeSuitCounts [4]int
+	for _, c := range board {
+		suitCounts[(c.GetSuit()-1)&3]++
+	}
+	for _, c := range hole {
+		suitCounts[(c.GetSuit()-1)&3]++
+		holeSuitCounts[(c.GetSuit()-1)&3]++
+	}
+	flush, flushDraw, backdoorFlushDraw := false, false, false
+	for i, count := range suitCounts {
+		if count >= 5 {
+			flush = true
+		} else if count == 4 && holeSuitCounts[i] > 0 {
+			flushDraw = true
+		} else if count == 3 && holeSuitCounts[i] > 0 && len(board) == 3 {
+			backdoorFlushDraw = true
+		}
+	}
+	if !flush && flushDraw {
+		result = append(result, FlushDraw)
+	}
+
+	// Straights need to be completed by ranks that would not complete a straight on the board by itself
+	straight, completing := isStraight(ours), 0
+	if !straight {
+		for _, r := range ranks {
+			bit := uint16(1) << (r - 1)
+			if isStraight(ours|bit) && !isStraight(boardOnly|bit) {
+				completing++
+			}
+		}
+	}
+	switch {
+	case completing >= 2:
+		result = append(result, OpenEndedDraw)
+	case completing == 1:
+		result = append(result, Gutshot)
+	}
+
+	if !flush && !flushDraw && backdoorFlushDraw {
+		result = append(result, BackdoorFlushDraw)
+	}
+	if !straight && completing == 0 && len(board) == 3 {
+		for i, x := range ranks {
+			for _, y := range ranks[i+1:] {
+				bits := uint16(1)<<(x-1) | uint16(1)<<(y-1)
+				if isStraight(ours|bits) && !isStraight(boardOnly|bits) {
+					return append(result, BackdoorStraightDraw)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// getRankMask returns a mask of the ranks among the cards, with bit 0 for a two and bit 12 for an ace.
+func getRankMask(hole, board []base.Card) uint16 {
+	mask := uint16(0)
+	for _, c := range hole {
+		mask |= 1 << (c.GetRank() - 1)
+	}
+	for _, c := range board {
+		mask |= 1 << (c.GetRank() - 1)
+	}
+	return mask
+}
+
+// isStraight returns whether a mask of ranks contains 5 consecutive ranks, where an ace can also be the lowest rank.
+func isStraight(mask uint16) bool {
+	extended := mask<<1 | mask>>12&1
+	for straight := uint16(0x1f); straight < 1<<14; straight <<= 1 {
+		if extended&straight == straight {
+			return true
+		}
+	}
+	return false
+}
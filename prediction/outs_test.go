@@ -0,0 +1,92 @@
+package prediction_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetOuts(t *testing.T) {
+	tests := []struct {
+		name     string
+		hole     string
+		board    string
+		opponent string
+		outs     string
+		draws    []prediction.Draw
+		nextCard float64
+		byRiver  float64
+	}{
+		// Fives and tens make a straight against top pair
+		{"open-ended on the turn", "9c8c", "7d6sKh2s", "AKo", "5c5d5h5s TcTdThTs",
+			[]prediction.Draw{prediction.OpenEndedDraw}, 8.0 / 46, 8.0 / 46},
+		// The 9 remaining hearts make a flush and the 3 other threes make a wheel against top pair
+		{"flush and gutshot on the flop", "5h4h", "Ah9h2c", "AKo", "2h3h6h7h8hThJhQhKh 3c3d3s",
+			[]prediction.Draw{prediction.FlushDraw, prediction.Gutshot}, 12.0 / 47, 1 - 35.0/47*34/46},
+		// Nothing is needed when already ahead
+		{"ahead", "AsAh", "Kd7c2h", "", "", []prediction.Draw{}, 0, 0},
+		{"backdoor draws", "Jh9h", "Th4c2s", "AA", "", []prediction.Draw{prediction.BackdoorFlushDraw,
+			prediction.BackdoorStraightDraw}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opponent prediction.Range
+			if tt.opponent != "" {
+				var err error
+				opponent, err = prediction.ParseRange(tt.opponent, nil, nil)
+				if err != nil {
+					t.Fatalf("ParseRange(%q) error = %v", tt.opponent, err)
+				}
+			}
+			got, err := prediction.GetOuts(cards(t, tt.hole), cards(t, tt.board), opponent, prediction.Options{})
+			if err != nil {
+				t.Fatalf("GetOuts() error = %v", err)
+			}
+			if want := base.NewCardSet(cards(t, tt.outs)...); base.NewCardSet(got.Cards...) != want ||
+				len(got.Cards) != want.Count() {
+				t.Errorf("GetOuts() cards = %v, want %v", got.Cards, tt.outs)
+			}
+			if !reflect.DeepEqual(got.Draws, tt.draws) {
+				t.Errorf("GetOuts() draws = %v, want %v", got.Draws, tt.draws)
+			}
+			if math.Abs(got.NextCard-tt.nextCard) > 1e-9 || math.Abs(got.ByRiver-tt.byRiver) > 1e-9 {
+				t.Errorf("GetOuts() = %v next card and %v by the river, want %v and %v", got.NextCard, got.ByRiver,
+					tt.nextCard, tt.byRiver)
+			}
+		})
+	}
+}
+
+func TestGetOutsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		hole     string
+		board    string
+		opponent string
+		options  prediction.Options
+	}{
+		{"river", "AsKs", "2c3d4h5s6c", "", prediction.Options{}},
+		{"preflop", "AsKs", "", "", prediction.Options{}},
+		{"hole", "AsKsQs", "2c3d4h", "", prediction.Options{}},
+		{"variant", "AsKs", "6c7d8h", "", prediction.Options{Variant: base.ShortDeckHoldem{}}},
+		{"blocked range", "AsKs", "Ah7d8h", "AA", prediction.Options{Dead: cards(t, "Ac")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opponent prediction.Range
+			if tt.opponent != "" {
+				var err error
+				opponent, err = prediction.ParseRange(tt.opponent, nil, nil)
+				if err != nil {
+					t.Fatalf("ParseRange(%q) error = %v", tt.opponent, err)
+				}
+			}
+			if _, err := prediction.GetOuts(cards(t, tt.hole), cards(t, tt.board), opponent, tt.options); err == nil {
+				t.Errorf("GetOuts() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// Potential is the strength of a hole against a random opponent hole, both as it stands and as it may change by the
+// end of the game.
+type Potential struct {
+	// Strength is the hand strength (HS), the probability that the hole is currently ahead of a random opponent hole,
+	// counting ties as half.
+	Strength float64 `json:"strength"`
+	// Positive is the positive potential (PPot), the probability that the hole ends up ahead when it is currently
+	// behind, counting ties as half.
+	Positive float64 `json:"positive"`
+	// Negative is the negative potential (NPot), the probability that the hole ends up behind when it is currently
+	// ahead, counting ties as half.
+	Negative float64 `json:"negative"`
+	// Effective is the effective hand strength (EHS) against all opponents, HS^n(1 - NPot) + (1 - HS^n)PPot, where HS^n
+	// is the probability of being ahead of n opponents assuming they hold holes independently.
+	Effective float64 `json:"effective"`
+}
+
+// Indices into the transitions between the current and final standing of the hole against an opponent hole.
+const (
+	ahead = iota
+	tied
+	behind
+)
+
+// GetPotential returns, given a hole and a board dealt up to some street of the variant, e.g. 3 to 5 cards in Texas
+// Hold'em, the current hand strength of the hole from the same counts as GetCurrentOrder, its positive and negative
+// potential from every transition between its current and final standing against every opponent hole over every
+// subsequent set of cards, and the effective hand strength that combines them. There is no potential once the board
+// is complete.
+func GetPotential(hole []base.Card, board []base.Card, options Options) (Potential, error) {
+	variant := options.getVariant()
+	if len(hole) != variant.GetHoleSize() {
+		return Potential{}, fmt.Errorf("potential can only be calculated for holes with %v cards",
+			variant.GetHoleSize())
+	}
+	if !base.IsStreet(variant, len(board)) {
+		return Potential{}, fmt.Errorf("potential can only be calculated for boards with %v cards",
+			formatStreets(variant))
+	}
+	if err := options.checkDead(hole, board); err != nil {
+		return Potential{}, err
+	}
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return Potential{}, err
+	}
+
+	deck := variant.NewDeck()
+	deck.Remove(hole)
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	levels := evaluate(deck.GetCards(), board, variant)
+	score, _ := variant.GetScore(hole, board)
+
+	// Sort every opponent hole by our current standing against it
+	var standings [3][][]base.Card
+	for _, level := range levels {
+		standing := tied
+		if level.getScore() > score {
+			standing = behind
+		} else if level.getScore() < score {
+			standing = ahead
+		}
+		standings[standing] = append(standings[standing], level.getHoles()...)
+	}
+	counts := [3]float64{}
+	for standing, holes := range standings {
+		counts[standing] = float64(len(holes))
+	}
+	total := counts[ahead] + counts[tied] + counts[behind]
+	if total == 0 {
+		return Potential{}, fmt.Errorf("there are no opponent holes left in the deck")
+	}
+
+	result := Potential{Strength: (counts[ahead] + counts[tied]/2) / total}
+
+	// Count the transitions from each current standing to each final standing
+	var totals [3]float64
+	var positive, negative float64
+	futureBoard := make([]base.Card, base.GetBoardSize(variant))
+	copy(futureBoard, board)
+	for _, subsequent := range base.GetCombinations(deck.GetCards(), len(futureBoard)-len(board)) {
+		copy(futureBoard[len(board):], subsequent)
+		dealt := base.NewCardSet(subsequent...)
+		finalScore, _ := variant.GetScore(hole, futureBoard)
+		for current, holes := range standings {
+			var transitions [3]float64
+			for _, opponentHole := range holes {
+				if dealt.Overlaps(base.NewCardSet(opponentHole...)) {
+					continue
+				}
+				opponentScore, _ := variant.GetScore(opponentHole, futureBoard)
+				if opponentScore > finalScore {
+					transitions[behind]++
+				} else if opponentScore == finalScore {
+					transitions[tied]++
+				} else {
+					transitions[ahead]++
+				}
+			}
+			totals[current] += transitions[ahead] + transitions[tied] + transitions[behind]
+			switch current {
+			case behind:
+				positive += transitions[ahead] + transitions[tied]/2
+			case tied:
+				positive += transitions[ahead] / 2
+				negative += transitions[behind] / 2
+			case ahead:
+				negative += transitions[behind] + transitions[tied]/2
+			}
+		}
+	}
+	if weight := totals[behind] + totals[tied]/2; weight > 0 {
+		result.Positive = positive / weight
+	}
+	if weight := totals[ahead] + totals[tied]/2; weight > 0 {
+		result.Negative = negative / weight
+	}
+
+	strength := math.Pow(result.Strength, float64(options.getOpponents()))
+	result.Effective = strength*(1-result.Negative) + (1-strength)*result.Positive
+	return result, nil
+}
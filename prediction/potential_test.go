@@ -0,0 +1,76 @@
+package prediction_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetPotential(t *testing.T) {
+	// With quads on the board, the ace kicker ties with 87 of 990 opponent holes and beats the rest
+	strength := (903 + 87.0/2) / 990
+	tests := []struct {
+		name      string
+		hole      string
+		board     string
+		opponents int
+		want      prediction.Potential
+	}{
+		{"river", "AsAh", "KsKhKdKc2c", 1, prediction.Potential{Strength: strength, Effective: strength}},
+		{"river against 2", "AsAh", "KsKhKdKc2c", 2, prediction.Potential{Strength: strength,
+			Effective: strength * strength}},
+		{"royal flush", "AsKs", "QsJsTs", 1, prediction.Potential{Strength: 1, Effective: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetPotential(cards(t, tt.hole), cards(t, tt.board),
+				prediction.Options{Opponents: tt.opponents})
+			if err != nil {
+				t.Fatalf("GetPotential() error = %v", err)
+			}
+			if math.Abs(got.Strength-tt.want.Strength) > 1e-9 || math.Abs(got.Positive-tt.want.Positive) > 1e-9 ||
+				math.Abs(got.Negative-tt.want.Negative) > 1e-9 || math.Abs(got.Effective-tt.want.Effective) > 1e-9 {
+				t.Errorf("GetPotential() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPotentialMatchesEquity(t *testing.T) {
+	// Against a single opponent, HS(1 - NPot) + (1 - HS)PPot works out to the probability of ending up ahead, counting
+	// ties as half, which is the equity
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+	}{
+		{"flush draw", "Ah5h", "Kh7h2c"},
+		{"overpair", "QsQd", "Jc8d3h"},
+		{"turn", "9c8c", "7d6sKh2s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, board := cards(t, tt.hole), cards(t, tt.board)
+			got, err := prediction.GetPotential(hole, board, prediction.Options{})
+			if err != nil {
+				t.Fatalf("GetPotential() error = %v", err)
+			}
+			want, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{})
+			if err != nil {
+				t.Fatalf("GetFutureOutcomes() error = %v", err)
+			}
+			if got.Positive <= 0 || got.Negative <= 0 || math.Abs(got.Effective-want.Equity) > 1e-9 {
+				t.Errorf("GetPotential() = %+v, want an effective strength of %v", got, want.Equity)
+			}
+		})
+	}
+}
+
+func TestGetPotentialErrors(t *testing.T) {
+	for _, board := range []string{"", "2c3d", "2c3d4h5s6c7d"} {
+		if _, err := prediction.GetPotential(cards(t, "AsKs"), cards(t, board), prediction.Options{}); err == nil {
+			t.Errorf("GetPotential() with board %q succeeded, want error", board)
+		}
+	}
+}
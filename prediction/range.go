@@ -0,0 +1,344 @@
+package prediction
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// maxDealAttempts is the number of times a game is redealt when sampling from ranges whose holes overlap, before
+// giving up on the ranges as incompatible.
+const maxDealAttempts = 10000
+
+// Range is a set of holes that a player may hold, keyed by the key of each hole, along with the relative weight of
+// each hole. Holes that are not in the range have a weight of 0.
+type Range map[base.Key]float64
+
+// NewRange returns an empty range.
+func NewRange() Range {
+	return make(Range)
+}
+
+// Add adds a hole of 2 cards to the range with the given weight, replacing any previous weight.
+func (r Range) Add(hole []base.Card, weight float64) error {
+	if len(hole) != 2 || hole[0] == hole[1] {
+		return fmt.Errorf("only holes of 2 distinct cards can be added to a range")
+	}
+	if weight < 0 {
+		return fmt.Errorf("range weights cannot be negative")
+	}
+	key, _ := base.GetKey(hole)
+	r[key] = weight
+	return nil
+}
+
+// GetWeight returns the weight of a hole in the range.
+func (r Range) GetWeight(hole []base.Card) float64 {
+	key, err := base.GetKey(hole)
+	if err != nil {
+		return 0
+	}
+	return r[key]
+}
+
+// GetHoles returns every hole in the range with a positive weight, in order of their keys.
+func (r Range) GetHoles() [][]base.Card {
+	keys := []base.Key{}
+	for key, weight := range r {
+		if weight > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(x, y int) bool { return keys[x] < keys[y] })
+	result := make([][]base.Card, len(keys))
+	for i, key := range keys {
+		result[i] = base.ParseKey(key)
+	}
+	return result
+}
+
+// combo is a hole from a range along with its weight.
+type combo struct {
+	hole   []base.Card
+	cards  base.CardSet
+	weight float64
+}
+
+// getCombos returns every hole in the range with a positive weight that does not overlap with the given cards.
+func (r Range) getCombos(known base.CardSet) []combo {
+	result := []combo{}
+	for _, hole := range r.GetHoles() {
+		cards := base.NewCardSet(hole...)
+		if !cards.Overlaps(known) {
+			result = append(result, combo{hole, cards, r.GetWeight(hole)})
+		}
+	}
+	return result
+}
+
+// overlaps returns whether the two sets of cards share any card.
+func overlaps(x, y []base.Card) bool {
+	return base.NewCardSet(x...).Overlaps(base.NewCardSet(y...))
+}
+
+// GetRangeOutcomes returns, given a hole and board of 3 to 5 cards, the probability that the hole will win, tie, and
+// lose against all opponents at the end of the game, assuming random subsequent cards and that each opponent holds a
+// hole from the given range.
+func GetRangeOutcomes(hole []base.Card, board []base.Card, opponent Range, options Options) (Outcome, error) {
+	if len(hole) != 2 {
+		return Outcome{}, fmt.Errorf("range outcomes can only be predicted for holes with 2 cards")
+	}
+	if err := options.checkDead(hole, board); err != nil {
+		return Outcome{}, err
+	}
+	ours := NewRange()
+	if err := ours.Add(hole, 1); err != nil {
+		return Outcome{}, err
+	}
+	return GetRangeVersusRangeOutcomes(ours, board, opponent, options)
+}
+
+// GetRangeVersusRangeOutcomes returns, given a board of 3 to 5 cards, the probability that a hole from our range will
+// win, tie, and lose against all opponents at the end of the game, assuming random subsequent cards and that each
+// opponent holds a hole from their range. Each combination of holes is weighted by the product of their weights. Holes
+// containing dead cards are left out of both ranges. Against more than one opponent, every combination of their holes
+// is too many to enumerate, so the outcome is estimated from DefaultSamples games, seeded so that it is reproducible.
+func GetRangeVersusRangeOutcomes(ours Range, board []base.Card, opponent Range, options Options) (Outcome, error) {
+	if _, ok := options.getVariant().(base.TexasHoldem); !ok {
+		return Outcome{}, fmt.Errorf("range outcomes can only be predicted for Texas Hold'em")
+	}
+	if len(board) == 0 {
+		return Outcome{}, fmt.Errorf("call EstimateRangeOutcomes to get range outcomes for an empty board")
+	}
+	if len(board) < 3 || len(board) > 5 {
+		return Outcome{}, fmt.Errorf("range outcomes can only be predicted for boards with 3 to 5 cards")
+	}
+	if err := options.checkDead(nil, board); err != nil {
+		return Outcome{}, err
+	}
+	if options.getOpponents() > 1 {
+		estimate, err := EstimateRangeOutcomes(ours, board, opponent, options,
+			Sampling{Rand: rand.New(rand.NewSource(fallbackSeed))})
+		return estimate.Outcome, err
+	}
+
+	boardCards := base.NewCardSet(board...)
+	known := boardCards.Union(base.NewCardSet(options.Dead...))
+	ourCombos := ours.getCombos(known)
+	opponentCombos := opponent.getCombos(known)
+	subsequent := base.GetCardSetCombinations(base.NewCardSet(base.NewDeck().GetCards()...).Difference(known),
+		5-len(board))
+
+	n := runtime.NumCPU()
+	var wg sync.WaitGroup
+	outcomes, weights := make([]Outcome, n), make([]float64, n)
+	for id := 0; id < n; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			opponentScores := make([]base.Score, len(opponentCombos))
+
+			lower := id * len(subsequent) / n
+			upper := (id + 1) * len(subsequent) / n
+			for i := lower; i < upper; i++ {
+				futureBoard := boardCards.Union(subsequent[i])
+				for j, c := range opponentCombos {
+					if c.cards.Overlaps(subsequent[i]) {
+						continue
+					}
+					opponentScores[j], _ = base.GetCardSetScore(futureBoard.Union(c.cards))
+				}
+
+				for _, ourCombo := range ourCombos {
+					if ourCombo.cards.Overlaps(subsequent[i]) {
+						continue
+					}
+					score, _ := base.GetCardSetScore(futureBoard.Union(ourCombo.cards))
+
+					better, same, worse := 0.0, 0.0, 0.0
+					for j, c := range opponentCombos {
+						if c.cards.Overlaps(subsequent[i]) || c.cards.Overlaps(ourCombo.cards) {
+							continue
+						}
+						if opponentScores[j] > score {
+							better += c.weight
+						} else if opponentScores[j] == score {
+							same += c.weight
+						} else {
+							worse += c.weight
+						}
+					}
+
+					// Every combination of holes and subsequent cards is weighted by the product of the hole weights
+					weight := ourCombo.weight * (better + same + worse)
+					outcome := getOutcome(better, same, worse, 1)
+					outcome.scale(weight)
+					outcomes[id].add(outcome)
+					weights[id] += weight
+				}
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	result, total := Outcome{}, 0.0
+	for id := 0; id < n; id++ {
+		result.add(outcomes[id])
+		total += weights[id]
+	}
+	if total == 0 {
+		return Outcome{}, fmt.Errorf("ranges have no holes that can coexist with each other and the board")
+	}
+	result.scale(1 / total)
+	return result, nil
+}
+
+// EstimateRangeOutcomes returns, given a board of 0 or 3 to 5 cards, an estimate of the probability that a hole from
+// our range will win, tie, and lose against all opponents at the end of the game, assuming random subsequent cards
+// and that each opponent holds a hole from their range. Each combination of holes is weighted by the product of their
+// weights. Holes containing dead cards are left out of both ranges.
+func EstimateRangeOutcomes(ours Range, board []base.Card, opponent Range, options Options,
+	sampling Sampling) (Estimate, error) {
+	if _, ok := options.getVariant().(base.TexasHoldem); !ok {
+		return Estimate{}, fmt.Errorf("range outcomes can only be estimated for Texas Hold'em")
+	}
+	if len(board) != 0 && (len(board) < 3 || len(board) > 5) {
+		return Estimate{}, fmt.Errorf("range outcomes can only be estimated for boards with 0 or 3 to 5 cards")
+	}
+	if err := options.checkDead(nil, board); err != nil {
+		return Estimate{}, err
+	}
+	opponents := options.getOpponents()
+	if 2+5+2*opponents+len(options.Dead) > len(base.NewDeck().GetCards()) {
+		return Estimate{}, fmt.Errorf("not enough cards to deal to %v opponents", opponents)
+	}
+	known := base.NewCardSet(board...).Union(base.NewCardSet(options.Dead...))
+	ourCombos := newComboSampler(ours.getCombos(known))
+	opponentCombos := newComboSampler(opponent.getCombos(known))
+	if ourCombos == nil || opponentCombos == nil {
+		return Estimate{}, fmt.Errorf("ranges have no holes that can coexist with the board")
+	}
+
+	samples := sampling.Samples
+	if samples <= 0 {
+		samples = DefaultSamples
+	}
+	r := sampling.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	deck := base.NewDeck()
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	hand := make([]base.Card, 7)
+	copy(hand[2:], board)
+	opponentHand := make([]base.Card, 7)
+	holes := make([][]base.Card, opponents+1)
+	remaining := make([]base.Card, 0, len(deck.GetCards()))
+
+	t := tally{}
+	dealt := base.CardSet(0)
+	for t.samples < samples {
+		// Deal every hole from its range, redealing all of them if any overlap so that each combination of holes is
+		// weighted by the product of their weights
+		attempts := 0
+	deal:
+		for {
+			if attempts++; attempts > maxDealAttempts {
+				return Estimate{}, fmt.Errorf("ranges have no holes that can coexist with each other and the board")
+			}
+			dealt = 0
+			for i := range holes {
+				var c combo
+				if i == 0 {
+					c = ourCombos.sample(r)
+				} else {
+					c = opponentCombos.sample(r)
+				}
+				if c.cards.Overlaps(dealt) {
+					continue deal
+				}
+				holes[i] = c.hole
+				dealt = dealt.Union(c.cards)
+			}
+			break
+		}
+
+		// Deal the subsequent cards from whatever remains
+		remaining = remaining[:0]
+		for _, c := range deck.GetCards() {
+			if !dealt.Contains(c) {
+				remaining = append(remaining, c)
+			}
+		}
+		for j := 0; j < 5-len(board); j++ {
+			k := j + r.Intn(len(remaining)-j)
+			remaining[j], remaining[k] = remaining[k], remaining[j]
+		}
+		copy(hand[2+len(board):], remaining[:5-len(board)])
+		copy(hand, holes[0])
+		score, _ := base.GetFastScore(hand)
+
+		copy(opponentHand[2:], hand[2:])
+		better, same := false, 0
+		for _, hole := range holes[1:] {
+			copy(opponentHand, hole)
+			opponentScore, _ := base.GetFastScore(opponentHand)
+			if opponentScore > score {
+				better = true
+				break
+			} else if opponentScore == score {
+				same++
+			}
+		}
+
+		if better {
+			t.add(Outcome{Lose: 1})
+		} else if same > 0 {
+			t.add(Outcome{Tie: 1, Equity: 1 / float64(same+1)})
+		} else {
+			t.add(Outcome{Win: 1, Equity: 1})
+		}
+		if sampling.StandardError > 0 && t.samples%sampleBatch == 0 &&
+			t.getEstimate().StandardError <= sampling.StandardError {
+			break
+		}
+	}
+	return t.getEstimate(), nil
+}
+
+// comboSampler samples holes from a set of combos in proportion to their weights.
+type comboSampler struct {
+	combos     []combo
+	cumulative []float64
+}
+
+// newComboSampler returns a sampler for the given combos, or nil if there is nothing to sample.
+func newComboSampler(combos []combo) *comboSampler {
+	if len(combos) == 0 {
+		return nil
+	}
+	s := &comboSampler{combos, make([]float64, len(combos))}
+	total := 0.0
+	for i, c := range combos {
+		total += c.weight
+		s.cumulative[i] = total
+	}
+	return s
+}
+
+func (s *comboSampler) sample(r *rand.Rand) combo {
+	x := r.Float64() * s.cumulative[len(s.cumulative)-1]
+	i := sort.SearchFloat64s(s.cumulative, x)
+	if i >= len(s.combos) {
+		i = len(s.combos) - 1
+	}
+	return s.combos[i]
+}
@@ -0,0 +1,162 @@
+package prediction_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+// newRange returns a range of the given holes, each with the weight that follows it.
+func newRange(t *testing.T, holes ...interface{}) prediction.Range {
+	t.Helper()
+	r := prediction.NewRange()
+	for i := 0; i < len(holes); i += 2 {
+		if err := r.Add(cards(t, holes[i].(string)), holes[i+1].(float64)); err != nil {
+			t.Fatalf("Add(%v) error = %v", holes[i], err)
+		}
+	}
+	return r
+}
+
+func TestRange(t *testing.T) {
+	r := newRange(t, "AsAh", 1.0, "KsKh", 0.5, "QsQh", 0.0)
+	if got := r.GetWeight(cards(t, "AhAs")); got != 1 {
+		t.Errorf("GetWeight(AhAs) = %v, want 1", got)
+	}
+	if got := r.GetWeight(cards(t, "KsKh")); got != 0.5 {
+		t.Errorf("GetWeight(KsKh) = %v, want 0.5", got)
+	}
+	if got := r.GetWeight(cards(t, "JsJh")); got != 0 {
+		t.Errorf("GetWeight(JsJh) = %v, want 0", got)
+	}
+	if got, want := len(r.GetHoles()), 2; got != want {
+		t.Errorf("GetHoles() = %v, want %v holes", r.GetHoles(), want)
+	}
+
+	for _, hole := range [][]base.Card{cards(t, "As"), cards(t, "AsKsQs"), {cards(t, "As")[0], cards(t, "As")[0]}} {
+		if err := r.Add(hole, 1); err == nil {
+			t.Errorf("Add(%v) succeeded, want error", hole)
+		}
+	}
+	if err := r.Add(cards(t, "AsKs"), -1); err == nil {
+		t.Errorf("Add() with a negative weight succeeded, want error")
+	}
+}
+
+func TestGetRangeVersusRangeOutcomes(t *testing.T) {
+	// Aces only win against the set of kings when an ace comes without the last king, which is 85 of the 990 runouts
+	board := cards(t, "Kd7c2h")
+	aces, kings := newRange(t, "AsAh", 1.0), newRange(t, "KsKh", 1.0)
+	want := prediction.Outcome{Win: 85.0 / 990, Lose: 905.0 / 990, Equity: 85.0 / 990}
+	got, err := prediction.GetRangeVersusRangeOutcomes(aces, board, kings, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetRangeVersusRangeOutcomes() error = %v", err)
+	}
+	checkOutcome(t, "GetRangeVersusRangeOutcomes()", got, want, 1e-9)
+
+	// Weights only matter relative to each other
+	heavy := newRange(t, "KsKh", 3.0)
+	got, err = prediction.GetRangeVersusRangeOutcomes(aces, board, heavy, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetRangeVersusRangeOutcomes() error = %v", err)
+	}
+	checkOutcome(t, "GetRangeVersusRangeOutcomes() with weights", got, want, 1e-9)
+
+	// Holes overlapping with the board or dead cards are left out, leaving only the set of kings, and the dead card
+	// can't come, leaving 83 of the 946 runouts
+	mixed := newRange(t, "KsKh", 1.0, "KdKc", 1.0, "7s7h", 1.0)
+	got, err = prediction.GetRangeVersusRangeOutcomes(aces, board, mixed, prediction.Options{Dead: cards(t, "7s")})
+	if err != nil {
+		t.Fatalf("GetRangeVersusRangeOutcomes() error = %v", err)
+	}
+	want = prediction.Outcome{Win: 83.0 / 946, Lose: 863.0 / 946, Equity: 83.0 / 946}
+	checkOutcome(t, "GetRangeVersusRangeOutcomes() with dead cards", got, want, 1e-9)
+}
+
+func TestGetRangeOutcomes(t *testing.T) {
+	// Against a range of every hole, the outcome is the same as against a random hole, up to sampling against more than
+	// one opponent
+	hole, board := cards(t, "AhAd"), cards(t, "Kc7s2d9h")
+	all := prediction.NewRange()
+	for _, h := range base.GetCombinations(base.NewDeck().GetCards(), 2) {
+		if err := all.Add(h, 1); err != nil {
+			t.Fatalf("Add(%v) error = %v", h, err)
+		}
+	}
+	for opponents := 1; opponents <= 2; opponents++ {
+		options := prediction.Options{Opponents: opponents}
+		want, err := prediction.GetFutureOutcomes(hole, board, options)
+		if err != nil {
+			t.Fatalf("GetFutureOutcomes() error = %v", err)
+		}
+		got, err := prediction.GetRangeOutcomes(hole, board, all, options)
+		if err != nil {
+			t.Fatalf("GetRangeOutcomes() error = %v", err)
+		}
+		tolerance := 1e-9
+		if opponents > 1 {
+			tolerance = 0.005
+		}
+		checkOutcome(t, "GetRangeOutcomes()", got, want, tolerance)
+	}
+}
+
+func TestEstimateRangeOutcomes(t *testing.T) {
+	board := cards(t, "Kd7c2h")
+	ours, opponent := newRange(t, "AsAh", 1.0, "AcAd", 1.0), newRange(t, "KsKh", 1.0, "QsQh", 2.0)
+	want, err := prediction.GetRangeVersusRangeOutcomes(ours, board, opponent, prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetRangeVersusRangeOutcomes() error = %v", err)
+	}
+	sampling := prediction.Sampling{Samples: 20000, Rand: rand.New(rand.NewSource(1))}
+	got, err := prediction.EstimateRangeOutcomes(ours, board, opponent, prediction.Options{}, sampling)
+	if err != nil {
+		t.Fatalf("EstimateRangeOutcomes() error = %v", err)
+	}
+	checkOutcome(t, "EstimateRangeOutcomes()", got.Outcome, want, 0.01)
+	if math.Abs(got.Equity-want.Equity) > 4*got.StandardError {
+		t.Errorf("EstimateRangeOutcomes() equity = %v with standard error %v, want %v", got.Equity,
+			got.StandardError, want.Equity)
+	}
+
+	// The same seed gives the same estimate
+	sampling.Rand = rand.New(rand.NewSource(1))
+	again, err := prediction.EstimateRangeOutcomes(ours, board, opponent, prediction.Options{}, sampling)
+	if err != nil {
+		t.Fatalf("EstimateRangeOutcomes() error = %v", err)
+	}
+	if !reflect.DeepEqual(again, got) {
+		t.Errorf("EstimateRangeOutcomes() = %+v and %+v with the same seed, want the same", got, again)
+	}
+}
+
+func TestRangeOutcomesErrors(t *testing.T) {
+	aces, kings := newRange(t, "AsAh", 1.0), newRange(t, "KsKh", 1.0)
+	tests := []struct {
+		name     string
+		board    string
+		opponent prediction.Range
+		options  prediction.Options
+	}{
+		{"empty board", "", kings, prediction.Options{}},
+		{"short board", "2c3d", kings, prediction.Options{}},
+		{"no coexisting holes", "Ks7c2h", kings, prediction.Options{}},
+		{"variant", "Kd7c2h", kings, prediction.Options{Variant: base.ShortDeckHoldem{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := prediction.GetRangeVersusRangeOutcomes(aces, cards(t, tt.board), tt.opponent,
+				tt.options); err == nil {
+				t.Errorf("GetRangeVersusRangeOutcomes() succeeded, want error")
+			}
+		})
+	}
+	if _, err := prediction.GetRangeOutcomes(cards(t, "AsAhKs"), cards(t, "Kd7c2h"), kings,
+		prediction.Options{}); err == nil {
+		t.Errorf("GetRangeOutcomes() of 3 cards succeeded, want error")
+	}
+}
@@ -0,0 +1,20 @@
+package prediction
+
+import (
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// GetShortDeckInitialOutcomes is like GetInitialOutcomes, but for short-deck (6+) Hold'em, which is played with a
+// deck of 36 cards where A-6-7-8-9 is the lowest straight and a flush beats a full house. There is no precomputed table
+// for short-deck, so every board is enumerated, which is exact against a single opponent but can take several seconds.
+func GetShortDeckInitialOutcomes(hole []base.Card, options Options) (Outcome, error) {
+	options.Variant = base.ShortDeckHoldem{}
+	return GetInitialOutcomes(hole, options)
+}
+
+// GetShortDeckFutureOutcomes is like GetFutureOutcomes, but for short-deck (6+) Hold'em, which is played with a deck of
+// 36 cards where A-6-7-8-9 is the lowest straight and a flush beats a full house.
+func GetShortDeckFutureOutcomes(hole []base.Card, board []base.Card, options Options) (Outcome, error) {
+	options.Variant = base.ShortDeckHoldem{}
+	return GetFutureOutcomes(hole, board, options)
+}
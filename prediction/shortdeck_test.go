@@ -0,0 +1,63 @@
+package prediction_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestGetShortDeckFutureOutcomes(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+		want  prediction.Outcome
+	}{
+		// The flush beats every full house, and only loses to the 28 of 406 holes with the last six for quads
+		{"flush", "AhKh", "QhJh6h6c6d", prediction.Outcome{Win: 378.0 / 406, Lose: 28.0 / 406, Equity: 378.0 / 406}},
+		// A-6-7-8-9 is the lowest straight, which ties with the 9 other holes of an ace and a nine, and loses to the 12
+		// holes of a nine and a ten
+		{"wheel", "As9c", "6d7h8sKcQd", prediction.Outcome{Win: 385.0 / 406, Tie: 9.0 / 406, Lose: 12.0 / 406,
+			Equity: (385 + 9.0/2) / 406}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prediction.GetShortDeckFutureOutcomes(cards(t, tt.hole), cards(t, tt.board),
+				prediction.Options{})
+			if err != nil {
+				t.Fatalf("GetShortDeckFutureOutcomes() error = %v", err)
+			}
+			checkOutcome(t, "GetShortDeckFutureOutcomes()", got, tt.want, 1e-9)
+		})
+	}
+}
+
+func TestGetShortDeckInitialOutcomes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerating every short-deck board is slow")
+	}
+	got, err := prediction.GetShortDeckInitialOutcomes(cards(t, "AsAh"), prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetShortDeckInitialOutcomes() error = %v", err)
+	}
+	// Aces are weaker against a random hole with fewer low cards in the deck
+	full, err := prediction.GetInitialOutcomes(cards(t, "AsAh"), prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetInitialOutcomes() error = %v", err)
+	}
+	if math.Abs(got.Win+got.Tie+got.Lose-1) > 1e-9 || got.Equity >= full.Equity || got.Equity < 0.7 {
+		t.Errorf("GetShortDeckInitialOutcomes() = %+v, want between 0.7 and %v", got, full.Equity)
+	}
+}
+
+func TestShortDeckOutcomesErrors(t *testing.T) {
+	// Fives and lower aren't in a short deck
+	if _, err := prediction.GetShortDeckFutureOutcomes(cards(t, "As5c"), cards(t, "6d7h8s"),
+		prediction.Options{}); err == nil {
+		t.Errorf("GetShortDeckFutureOutcomes() with a five succeeded, want error")
+	}
+	if _, err := prediction.GetShortDeckInitialOutcomes(cards(t, "As2c"), prediction.Options{}); err == nil {
+		t.Errorf("GetShortDeckInitialOutcomes() with a deuce succeeded, want error")
+	}
+}
@@ -0,0 +1,245 @@
+package prediction
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/shishichen/strategic-parrot/base"
+)
+
+// DefaultSamples is the number of games sampled by EstimateOutcomes when no sample budget is given.
+const DefaultSamples = 100000
+
+// sampleBatch is the number of games sampled between checks of the standard error.
+const sampleBatch = 1000
+
+// fallbackSeed seeds the games sampled by predictions that are otherwise exact, e.g. against more than one opponent,
+// so that they always return the same outcome.
+const fallbackSeed = 1
+
+// z is the number of standard errors on either side of an estimate covered by its 95% confidence interval.
+const z = 1.96
+
+// Sampling configures how outcomes are estimated by sampling random games.
+type Sampling struct {
+	// Samples is the maximum number of games to sample. Zero is treated as DefaultSamples.
+	Samples int
+	// StandardError, if positive, stops sampling early once the standard error of the equity falls below it.
+	StandardError float64
+	// Rand is the source of randomness, which can be seeded for reproducible estimates. If nil, a source seeded with
+	// the current time is used.
+	Rand *rand.Rand
+}
+
+// Interval is a confidence interval.
+type Interval struct {
+	Low  float64
+	High float64
+}
+
+// Estimate is an outcome estimated by sampling random games, along with 95% confidence intervals.
+type Estimate struct {
+	Outcome
+	WinInterval    Interval
+	TieInterval    Interval
+	LoseInterval   Interval
+	EquityInterval Interval
+	// StandardError is the standard error of the equity.
+	StandardError float64
+	// Samples is the number of games sampled.
+	Samples int
+}
+
+// EstimateOutcomes returns, given a hole and board that is empty or dealt up to some street of the variant, an estimate
+// of the probability that the hole will win, tie, and lose against all opponents at the end of the game, assumming
+// random subsequent cards, by sampling random games dealt to every opponent from the same deck. Unlike
+// GetFutureOutcomes, this trades precision for speed.
+func EstimateOutcomes(hole []base.Card, board []base.Card, options Options, sampling Sampling) (Estimate, error) {
+	variant := options.getVariant()
+	if len(hole) != variant.GetHoleSize() {
+		return Estimate{}, fmt.Errorf("outcomes can only be estimated for holes with %v cards", variant.GetHoleSize())
+	}
+	if len(board) != 0 && !base.IsStreet(variant, len(board)) {
+		return Estimate{}, fmt.Errorf("outcomes can only be estimated for boards with 0 or %v cards",
+			formatStreets(variant))
+	}
+	if err := options.checkDead(hole, board); err != nil {
+		return Estimate{}, err
+	}
+	if err := checkDeck(variant, hole, board, options.Dead); err != nil {
+		return Estimate{}, err
+	}
+	if err := options.checkOpponents(len(hole), base.GetBoardSize(variant)); err != nil {
+		return Estimate{}, err
+	}
+
+	samples := sampling.Samples
+	if samples <= 0 {
+		samples = DefaultSamples
+	}
+	r := sampling.Rand
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	t := tally{}
+	for t.samples < samples {
+		batch := sampleBatch
+		if samples-t.samples < batch {
+			batch = samples - t.samples
+		}
+		simulate(hole, board, base.GetBoardSize(variant), options, batch, r, &t)
+		if sampling.StandardError > 0 && t.getEstimate().StandardError <= sampling.StandardError {
+			break
+		}
+	}
+	return t.getEstimate(), nil
+}
+
+// tally accumulates the outcomes of simulated games, either of a single pot or of a hi-lo split pot.
+type tally struct {
+	outcome       Outcome     // sum of the outcomes of every game
+	hiLo          HiLoOutcome // sum of the hi-lo outcomes of every game
+	equity        float64     // sum of the equities of every game
+	equitySquares float64     // sum of the squared equities of every game
+	samples       int
+}
+
+// add adds the outcome of a single game.
+func (t *tally) add(outcome Outcome) {
+	t.outcome.add(outcome)
+	t.addEquity(outcome.Equity)
+}
+
+// addHiLo adds the hi-lo outcome of a single game.
+func (t *tally) addHiLo(outcome HiLoOutcome) {
+	t.hiLo.add(outcome)
+	t.addEquity(outcome.Equity)
+}
+
+// addEquity counts a single game with the given equity.
+func (t *tally) addEquity(equity float64) {
+	t.equity += equity
+	t.equitySquares += equity * equity
+	t.samples++
+}
+
+// getStandardError returns the standard error of the average equity of the games so far.
+func (t *tally) getStandardError() float64 {
+	if t.samples < 2 {
+		return math.Inf(1)
+	}
+	n := float64(t.samples)
+	mean := t.equity / n
+	variance := (t.equitySquares - n*mean*mean) / (n - 1)
+	return math.Sqrt(math.Max(variance, 0) / n)
+}
+
+// getEstimate returns the average outcome of the games so far, along with its confidence intervals.
+func (t *tally) getEstimate() Estimate {
+	if t.samples == 0 {
+		return Estimate{StandardError: math.Inf(1)}
+	}
+	n := float64(t.samples)
+	mean := t.outcome
+	mean.scale(1 / n)
+
+	// Win, tie, and lose are proportions, so their standard errors follow directly from the proportion
+	proportionError := func(p float64) float64 {
+		return math.Sqrt(p * (1 - p) / n)
+	}
+	equityError := t.getStandardError()
+
+	return Estimate{
+		Outcome:        mean,
+		WinInterval:    getInterval(mean.Win, proportionError(mean.Win)),
+		TieInterval:    getInterval(mean.Tie, proportionError(mean.Tie)),
+		LoseInterval:   getInterval(mean.Lose, proportionError(mean.Lose)),
+		EquityInterval: getInterval(mean.Equity, equityError),
+		StandardError:  equityError,
+		Samples:        t.samples,
+	}
+}
+
+// getHiLoEstimate returns the average hi-lo outcome of the games so far, along with its confidence interval.
+func (t *tally) getHiLoEstimate() HiLoEstimate {
+	if t.samples == 0 {
+		return HiLoEstimate{StandardError: math.Inf(1)}
+	}
+	mean := t.hiLo
+	mean.scale(1 / float64(t.samples))
+	equityError := t.getStandardError()
+
+	return HiLoEstimate{
+		HiLoOutcome:    mean,
+		EquityInterval: getInterval(mean.Equity, equityError),
+		StandardError:  equityError,
+		Samples:        t.samples,
+	}
+}
+
+// getInterval returns the 95% confidence interval around an estimated probability, clamped to [0, 1].
+func getInterval(estimate, standardError float64) Interval {
+	return Interval{math.Max(estimate-z*standardError, 0), math.Min(estimate+z*standardError, 1)}
+}
+
+// sampleOutcome returns the average outcome of DefaultSamples games with random opponent holes and random subsequent
+// cards up to a board of the given size, seeded with fallbackSeed. Exact predictions use it against more than one
+// opponent, where enumerating every joint deal of opponent holes is infeasible.
+func sampleOutcome(hole, board []base.Card, size int, options Options) (Outcome, error) {
+	if err := options.checkOpponents(len(hole), size); err != nil {
+		return Outcome{}, err
+	}
+	t := tally{}
+	simulate(hole, board, size, options, DefaultSamples, rand.New(rand.NewSource(fallbackSeed)), &t)
+	return t.getEstimate().Outcome, nil
+}
+
+// simulate plays the given number of games with random subsequent cards up to a board of the given size and random
+// opponent holes of the same size as the hole, and adds their outcomes to the tally. Unlike getOutcome, opponent holes
+// never share cards with each other.
+func simulate(hole, board []base.Card, size int, options Options, samples int, r *rand.Rand, t *tally) {
+	variant := options.getVariant()
+	deck := variant.NewDeck()
+	deck.Remove(hole)
+	deck.Remove(board)
+	deck.Remove(options.Dead)
+	opponents := options.getOpponents()
+	cards := deck.GetCards()
+	dealt := size - len(board)
+	needed := dealt + len(hole)*opponents
+
+	futureBoard := make([]base.Card, size)
+	copy(futureBoard, board)
+
+	for i := 0; i < samples; i++ {
+		// Only shuffle as many cards as will be dealt
+		for j := 0; j < needed; j++ {
+			k := j + r.Intn(len(cards)-j)
+			cards[j], cards[k] = cards[k], cards[j]
+		}
+		copy(futureBoard[len(board):], cards[:dealt])
+		ourScore, _ := variant.GetScore(hole, futureBoard)
+
+		better, same := false, 0
+		for j := 0; j < opponents && !better; j++ {
+			start := dealt + len(hole)*j
+			opponentScore, _ := variant.GetScore(cards[start:start+len(hole)], futureBoard)
+			if opponentScore > ourScore {
+				better = true
+			} else if opponentScore == ourScore {
+				same++
+			}
+		}
+
+		if better {
+			t.add(Outcome{Lose: 1})
+		} else if same > 0 {
+			t.add(Outcome{Tie: 1, Equity: 1 / float64(same+1)})
+		} else {
+			t.add(Outcome{Win: 1, Equity: 1})
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package prediction_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+func TestEstimateOutcomes(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+	}{
+		{"preflop", "AsAh", ""},
+		{"flop", "8h7h", "9h6c2d"},
+		{"turn", "AhAd", "Kc7s2d9h"},
+		{"river", "AsAh", "KsKhKdKc2c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hole, board := cards(t, tt.hole), cards(t, tt.board)
+			var want prediction.Outcome
+			var err error
+			if len(board) == 0 {
+				want, err = prediction.GetInitialOutcomes(hole, prediction.Options{})
+			} else {
+				want, err = prediction.GetFutureOutcomes(hole, board, prediction.Options{})
+			}
+			if err != nil {
+				t.Fatalf("exact outcomes error = %v", err)
+			}
+
+			got, err := prediction.EstimateOutcomes(hole, board, prediction.Options{},
+				prediction.Sampling{Samples: 20000, Rand: rand.New(rand.NewSource(1))})
+			if err != nil {
+				t.Fatalf("EstimateOutcomes() error = %v", err)
+			}
+			if got.Samples != 20000 {
+				t.Errorf("EstimateOutcomes() sampled %v games, want 20000", got.Samples)
+			}
+			checkOutcome(t, "EstimateOutcomes()", got.Outcome, want, 0.01)
+			if math.Abs(got.Equity-want.Equity) > 4*got.StandardError {
+				t.Errorf("EstimateOutcomes() equity = %v with standard error %v, want %v", got.Equity,
+					got.StandardError, want.Equity)
+			}
+			if got.Equity < got.EquityInterval.Low || got.Equity > got.EquityInterval.High {
+				t.Errorf("EstimateOutcomes() equity interval = %+v, want it to contain %v", got.EquityInterval,
+					got.Equity)
+			}
+		})
+	}
+}
+
+func TestEstimateOutcomesSeed(t *testing.T) {
+	hole, board := cards(t, "8h7h"), cards(t, "9h6c2d")
+	estimate := func(seed int64) prediction.Estimate {
+		got, err := prediction.EstimateOutcomes(hole, board, prediction.Options{Opponents: 3},
+			prediction.Sampling{Samples: 5000, Rand: rand.New(rand.NewSource(seed))})
+		if err != nil {
+			t.Fatalf("EstimateOutcomes() error = %v", err)
+		}
+		return got
+	}
+	if x, y := estimate(1), estimate(1); !reflect.DeepEqual(x, y) {
+		t.Errorf("EstimateOutcomes() = %+v and %+v with the same seed, want the same", x, y)
+	}
+	if x, y := estimate(1), estimate(2); reflect.DeepEqual(x, y) {
+		t.Errorf("EstimateOutcomes() = %+v with different seeds, want different estimates", x)
+	}
+}
+
+func TestEstimateOutcomesStandardError(t *testing.T) {
+	got, err := prediction.EstimateOutcomes(cards(t, "AhAd"), cards(t, "Kc7s2d9h"), prediction.Options{},
+		prediction.Sampling{StandardError: 0.01, Rand: rand.New(rand.NewSource(1))})
+	if err != nil {
+		t.Fatalf("EstimateOutcomes() error = %v", err)
+	}
+	if got.StandardError > 0.01 || got.Samples >= prediction.DefaultSamples {
+		t.Errorf("EstimateOutcomes() = %+v, want a standard error of at most 0.01 before %v samples", got,
+			prediction.DefaultSamples)
+	}
+}
+
+func TestEstimateOutcomesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		hole  string
+		board string
+	}{
+		{"short hole", "As", "2c3d4h"},
+		{"short board", "AsKs", "2c3d"},
+		{"long board", "AsKs", "2c3d4h5s6c7d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := prediction.EstimateOutcomes(cards(t, tt.hole), cards(t, tt.board), prediction.Options{},
+				prediction.Sampling{}); err == nil {
+				t.Errorf("EstimateOutcomes() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package prediction_test
+
+import (
+	"testing"
+
+	"github.com/shishichen/strategic-parrot/base"
+	"github.com/shishichen/strategic-parrot/prediction"
+)
+
+// flopHoldem is Texas Hold'em where the board is only dealt a flop.
+type flopHoldem struct {
+	base.TexasHoldem
+}
+
+func (flopHoldem) GetStreets() []int {
+	return []int{3}
+}
+
+func TestVariant(t *testing.T) {
+	// With no more cards to come, the future outcome is the current outcome in Texas Hold'em
+	hole, board := cards(t, "9c8c"), cards(t, "7d6sKh")
+	for opponents := 1; opponents <= 3; opponents++ {
+		_, _, _, _, _, want, err := prediction.GetCurrentOrder(hole, board, prediction.Options{Opponents: opponents})
+		if err != nil {
+			t.Fatalf("GetCurrentOrder() error = %v", err)
+		}
+		got, err := prediction.GetFutureOutcomes(hole, board, prediction.Options{Opponents: opponents,
+			Variant: flopHoldem{}})
+		if err != nil {
+			t.Fatalf("GetFutureOutcomes() error = %v", err)
+		}
+		checkOutcome(t, "GetFutureOutcomes()", got, want, 1e-9)
+	}
+
+	if _, err := prediction.GetFutureOutcomes(hole, cards(t, "7d6sKh2c"), prediction.Options{
+		Variant: flopHoldem{}}); err == nil {
+		t.Errorf("GetFutureOutcomes() after the last street succeeded, want error")
+	}
+}
+
+func TestVariantHoleSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		hole    string
+		variant base.Variant
+	}{
+		{"texas hold'em", "AsKsQs", base.TexasHoldem{}},
+		{"short deck", "AsKsQs", base.ShortDeckHoldem{}},
+		{"omaha", "AsKs", base.Omaha{}},
+		{"five card omaha", "AsKsQsJs", base.FiveCardOmaha{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := prediction.Options{Variant: tt.variant}
+			if _, err := prediction.GetFutureOutcomes(cards(t, tt.hole), cards(t, "2c3d4h"), options); err == nil {
+				t.Errorf("GetFutureOutcomes() succeeded, want error")
+			}
+			if _, _, _, _, _, _, err := prediction.GetCurrentOrder(cards(t, tt.hole), cards(t, "2c3d4h"),
+				options); err == nil {
+				t.Errorf("GetCurrentOrder() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestFiveCardOmaha(t *testing.T) {
+	// Only 2 of the 5 hole cards can be used, so four aces are only a pair, which loses to two pair or better
+	got, err := prediction.GetOmahaFutureOutcomes(cards(t, "AsAhAdAcKs"), cards(t, "9c8d7h2s3c"), prediction.Options{})
+	if err != nil {
+		t.Fatalf("GetOmahaFutureOutcomes() error = %v", err)
+	}
+	if got.Lose <= 0 || got.Win >= 1 {
+		t.Errorf("GetOmahaFutureOutcomes() = %+v, want some losses", got)
+	}
+}